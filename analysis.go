@@ -0,0 +1,54 @@
+package mtg
+
+import (
+	"sort"
+	"strings"
+)
+
+// ManaCurve counts the cards by their converted mana cost. Cards without a mana cost (like lands)
+// are counted at 0 and fractional costs (like {½}) are rounded down. Cards lacking the cmc are
+// counted by their mana cost, see Card.ComputedCMC.
+func ManaCurve(cards []*Card) map[int]int {
+	curve := make(map[int]int)
+	for _, c := range cards {
+		curve[int(c.ComputedCMC())]++
+	}
+	return curve
+}
+
+// Artists returns the distinct artists of the cards, sorted alphabetically. Cards without an
+// artist are skipped.
+func Artists(cards []*Card) []string {
+	seen := make(map[string]bool)
+	var artists []string
+	for _, c := range cards {
+		artist := strings.TrimSpace(c.Artist)
+		if artist == "" || seen[artist] {
+			continue
+		}
+		seen[artist] = true
+		artists = append(artists, artist)
+	}
+	sort.Strings(artists)
+	return artists
+}
+
+// GroupBySet groups the cards by the code of their set. The cards keep their order within each
+// group. Use Set.Name of the fetched sets (see FetchSets) to display the groups.
+func GroupBySet(cards []*Card) map[SetCode][]*Card {
+	groups := make(map[SetCode][]*Card)
+	for _, c := range cards {
+		groups[c.Set] = append(groups[c.Set], c)
+	}
+	return groups
+}
+
+// GroupBySetName groups the cards by the name of their set like GroupBySet, which saves fetching
+// the sets just to display their names.
+func GroupBySetName(cards []*Card) map[string][]*Card {
+	groups := make(map[string][]*Card)
+	for _, c := range cards {
+		groups[c.SetName] = append(groups[c.SetName], c)
+	}
+	return groups
+}
@@ -0,0 +1,72 @@
+package mtg
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_ManaCurve(t *testing.T) {
+	Convey("When building a mana curve", t, func() {
+		cards := []*Card{
+			{Name: "Forest", CMC: 0},
+			{Name: "Llanowar Elves", CMC: 1},
+			{Name: "Giant Growth", CMC: 1},
+			{Name: "Grizzly Bears", CMC: 2},
+			{Name: "Craw Wurm", CMC: 6},
+			{Name: "Little Girl", CMC: 0.5},
+		}
+		curve := ManaCurve(cards)
+
+		Convey("the cards should be counted by their cmc", func() {
+			So(curve, ShouldResemble, map[int]int{0: 2, 1: 2, 2: 1, 6: 1})
+		})
+		Convey("no cards should result in an empty curve", func() {
+			So(ManaCurve(nil), ShouldBeEmpty)
+		})
+	})
+}
+
+func Test_Artists(t *testing.T) {
+	Convey("When listing the artists of cards", t, func() {
+		cards := []*Card{
+			{Name: "Lightning Bolt", Artist: "Christopher Rush"},
+			{Name: "Earthquake", Artist: "Dan Frazier"},
+			{Name: "Black Lotus", Artist: "Christopher Rush"},
+			{Name: "Goblin", Artist: ""},
+			{Name: "Shock", Artist: " "},
+		}
+
+		Convey("each artist should be returned once in sorted order", func() {
+			So(Artists(cards), ShouldResemble, []string{"Christopher Rush", "Dan Frazier"})
+		})
+		Convey("no cards should result in no artists", func() {
+			So(Artists(nil), ShouldBeEmpty)
+		})
+	})
+}
+
+func Test_GroupBySet(t *testing.T) {
+	Convey("When grouping cards by set", t, func() {
+		cards := []*Card{
+			{Name: "Lightning Bolt", Set: "LEA", SetName: "Limited Edition Alpha"},
+			{Name: "Shock", Set: "M19", SetName: "Core Set 2019"},
+			{Name: "Earthquake", Set: "LEA", SetName: "Limited Edition Alpha"},
+		}
+
+		Convey("the cards should be grouped by set code in their order", func() {
+			groups := GroupBySet(cards)
+			So(groups, ShouldHaveLength, 2)
+			So(groups["LEA"], ShouldResemble, []*Card{cards[0], cards[2]})
+			So(groups["M19"], ShouldResemble, []*Card{cards[1]})
+		})
+		Convey("the cards should be grouped by set name", func() {
+			groups := GroupBySetName(cards)
+			So(groups, ShouldHaveLength, 2)
+			So(groups["Limited Edition Alpha"], ShouldResemble, []*Card{cards[0], cards[2]})
+		})
+		Convey("no cards should result in no groups", func() {
+			So(GroupBySet(nil), ShouldBeEmpty)
+		})
+	})
+}
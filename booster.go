@@ -0,0 +1,103 @@
+package mtg
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+)
+
+// BuildBooster builds a booster on the client: for every slot of the booster definition (like
+// Set.Booster) a random card of the pool is picked. A slot with several alternatives, like
+// ["rare", "mythic rare"], uses one of the alternatives the pool has cards for at random. Slots
+// are filled with cards of the named rarity; "land" slots are filled with basic lands. Slots
+// which name no rarity (like "marketing" or "token") and slots for which the pool has no cards
+// left are skipped, and every card of the pool is used at most once. If rnd is nil, the default
+// source of math/rand is used.
+func BuildBooster(booster []BoosterContent, pool []*Card, rnd *rand.Rand) []*Card {
+	intn := rand.Intn
+	if rnd != nil {
+		intn = rnd.Intn
+	}
+
+	byRarity := make(map[Rarity][]*Card)
+	for _, c := range pool {
+		r := c.RarityValue()
+		byRarity[r] = append(byRarity[r], c)
+	}
+
+	var cards []*Card
+	for _, slot := range booster {
+		// only alternatives the pool still has cards for are considered
+		var rarities []Rarity
+		for _, name := range slot {
+			if r, ok := slotRarity(name); ok && len(byRarity[r]) > 0 {
+				rarities = append(rarities, r)
+			}
+		}
+		if len(rarities) == 0 {
+			continue
+		}
+		r := rarities[intn(len(rarities))]
+		candidates := byRarity[r]
+		i := intn(len(candidates))
+		cards = append(cards, candidates[i])
+		candidates[i] = candidates[len(candidates)-1]
+		byRarity[r] = candidates[:len(candidates)-1]
+	}
+	return cards
+}
+
+// slotRarity returns the rarity of the cards of a booster slot like "rare" or "land".
+func slotRarity(name string) (Rarity, bool) {
+	name = strings.ToLower(name)
+	if name == "land" {
+		return RarityBasicLand, true
+	}
+	r, ok := rarityNames[name]
+	return r, ok
+}
+
+// PullProbability returns the probability to open at least one copy of the card in a booster of
+// the set. The cards of the set are fetched to know how many cards share each rarity.
+//
+// The API only tells which rarities a slot may contain, not how likely they are, and nothing
+// about print runs. So the probability is an estimate which assumes that every alternative of a
+// slot (like "rare" and "mythic rare") is equally likely and that all cards of a rarity are
+// equally likely within a slot. Cards which are not part of the set have a probability of 0.
+func (s *Set) PullProbability(card *Card) (float64, error) {
+	if len(s.Booster) == 0 {
+		return 0, errors.New("the set has no booster")
+	}
+	cards, err := s.SetCode.Cards()
+	if err != nil {
+		return 0, err
+	}
+	return pullProbability(s.Booster, cards, card), nil
+}
+
+func pullProbability(booster []BoosterContent, cards []*Card, card *Card) float64 {
+	rarity := card.RarityValue()
+	inSet := false
+	count := 0
+	for _, c := range cards {
+		if c.RarityValue() == rarity {
+			count++
+		}
+		if c.Id == card.Id {
+			inSet = true
+		}
+	}
+	if !inSet || count == 0 {
+		return 0
+	}
+
+	none := 1.0
+	for _, slot := range booster {
+		for _, name := range slot {
+			if r, ok := slotRarity(name); ok && r == rarity {
+				none *= 1 - 1/float64(len(slot))/float64(count)
+			}
+		}
+	}
+	return 1 - none
+}
@@ -0,0 +1,98 @@
+package mtg
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_BuildBooster(t *testing.T) {
+	Convey("When building a booster on the client", t, func() {
+		pool := []*Card{
+			{Name: "Shock", Rarity: "Common"},
+			{Name: "Giant Growth", Rarity: "Common"},
+			{Name: "Counterspell", Rarity: "Uncommon"},
+			{Name: "Shivan Dragon", Rarity: "Rare"},
+			{Name: "Forest", Rarity: "Basic Land"},
+		}
+		rnd := rand.New(rand.NewSource(1))
+
+		Convey("every slot should be filled with a card of its rarity", func() {
+			booster := []BoosterContent{{"rare", "mythic rare"}, {"uncommon"}, {"common"}, {"land"}}
+			cards := BuildBooster(booster, pool, rnd)
+			So(cards, ShouldHaveLength, 4)
+			So(cards[0].Name, ShouldEqual, "Shivan Dragon")
+			So(cards[1].Name, ShouldEqual, "Counterspell")
+			So(cards[2].RarityValue(), ShouldEqual, RarityCommon)
+			So(cards[3].Name, ShouldEqual, "Forest")
+		})
+
+		Convey("cards should not be used twice", func() {
+			booster := []BoosterContent{{"common"}, {"common"}, {"common"}}
+			cards := BuildBooster(booster, pool, rnd)
+			So(cards, ShouldHaveLength, 2)
+			So(cards[0], ShouldNotEqual, cards[1])
+		})
+
+		Convey("slots without rarity should be skipped", func() {
+			booster := []BoosterContent{{"marketing"}, {}, {"rare"}}
+			cards := BuildBooster(booster, pool, nil)
+			So(cards, ShouldHaveLength, 1)
+			So(cards[0].Name, ShouldEqual, "Shivan Dragon")
+		})
+	})
+}
+
+func Test_PullProbability(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When computing the probability to open a card", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=KTK",
+			httpmock.NewStringResponder(200, `{"cards":[
+				{"name":"Sorin, Solemn Visitor","rarity":"Mythic Rare","set":"KTK","id":"a"},
+				{"name":"Wooded Foothills","rarity":"Rare","set":"KTK","id":"b"},
+				{"name":"Bloodstained Mire","rarity":"Rare","set":"KTK","id":"c"},
+				{"name":"Mardu Hordechief","rarity":"Common","set":"KTK","id":"d"},
+				{"name":"Debilitating Injury","rarity":"Common","set":"KTK","id":"e"}
+			]}`))
+		set := &Set{SetCode: "KTK", Booster: []BoosterContent{{"rare", "mythic rare"}, {"common"}, {"common"}}}
+
+		Convey("alternatives of a slot should be equally likely", func() {
+			p, err := set.PullProbability(&Card{Rarity: "Mythic Rare", Id: "a"})
+			So(err, ShouldBeNil)
+			So(p, ShouldAlmostEqual, 0.5)
+
+			p, err = set.PullProbability(&Card{Rarity: "Rare", Id: "b"})
+			So(err, ShouldBeNil)
+			So(p, ShouldAlmostEqual, 0.25)
+		})
+
+		Convey("several slots should raise the probability", func() {
+			p, err := set.PullProbability(&Card{Rarity: "Common", Id: "d"})
+			So(err, ShouldBeNil)
+			So(p, ShouldAlmostEqual, 0.75)
+		})
+
+		Convey("cards of other sets should have no probability", func() {
+			p, err := set.PullProbability(&Card{Rarity: "Common", Id: "x"})
+			So(err, ShouldBeNil)
+			So(p, ShouldEqual, 0)
+		})
+
+		Convey("sets without booster should return an error", func() {
+			_, err := (&Set{SetCode: "KTK"}).PullProbability(&Card{Id: "a"})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("errors should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=KTK",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			_, err := set.PullProbability(&Card{Id: "a"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,82 @@
+package mtg
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of making a request while the circuit breaker is open.
+// See WithCircuitBreaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// WithCircuitBreaker enables a circuit breaker which opens after threshold consecutive failed
+// requests. Failed requests are requests which could not be made and requests answered with a
+// 5xx status; requests canceled by the caller, including an expired deadline of its context, are
+// not counted. While the breaker is open, all requests fail immediately with ErrCircuitOpen.
+// After the cooldown a single probe request is let through: if it succeeds the breaker closes
+// again, otherwise it stays open for another cooldown. A threshold of zero disables the breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *config) {
+		if threshold <= 0 {
+			c.breaker = nil
+			return
+		}
+		c.breaker = &circuitBreaker{
+			threshold: threshold,
+			cooldown:  cooldown,
+			now:       time.Now,
+		}
+	}
+}
+
+// allow returns ErrCircuitOpen if no request should be made.
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.failures < cb.threshold {
+		return nil
+	}
+	if cb.probing || cb.now().Before(cb.openUntil) {
+		return ErrCircuitOpen
+	}
+	cb.probing = true
+	return nil
+}
+
+// release gives up a request which was allowed without recording a result, for example because
+// the caller canceled it. A probe may be made by the next request instead.
+func (cb *circuitBreaker) release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+}
+
+// record stores the result of a request which was allowed.
+func (cb *circuitBreaker) record(success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+	if success {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.failures >= cb.threshold {
+		cb.openUntil = cb.now().Add(cb.cooldown)
+	}
+}
@@ -0,0 +1,120 @@
+package mtg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_CircuitBreaker(t *testing.T) {
+	Convey("With a circuit breaker", t, func() {
+		now := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+		cb := &circuitBreaker{threshold: 2, cooldown: time.Minute, now: func() time.Time { return now }}
+
+		Convey("requests should be allowed while there are less failures than the threshold", func() {
+			So(cb.allow(), ShouldBeNil)
+			cb.record(false)
+			So(cb.allow(), ShouldBeNil)
+		})
+
+		Convey("a success should reset the failures", func() {
+			cb.record(false)
+			cb.record(true)
+			cb.record(false)
+			So(cb.allow(), ShouldBeNil)
+		})
+
+		Convey("after enough failures", func() {
+			cb.record(false)
+			cb.record(false)
+
+			Convey("the circuit should be open", func() {
+				So(cb.allow(), ShouldEqual, ErrCircuitOpen)
+			})
+
+			Convey("after the cooldown a single probe should be allowed", func() {
+				now = now.Add(time.Minute)
+				So(cb.allow(), ShouldBeNil)
+				So(cb.allow(), ShouldEqual, ErrCircuitOpen)
+
+				Convey("a successful probe should close the circuit", func() {
+					cb.record(true)
+					So(cb.allow(), ShouldBeNil)
+					So(cb.allow(), ShouldBeNil)
+				})
+
+				Convey("a released probe should let the next request probe", func() {
+					cb.release()
+					So(cb.allow(), ShouldBeNil)
+				})
+
+				Convey("a failed probe should open it for another cooldown", func() {
+					cb.record(false)
+					So(cb.allow(), ShouldEqual, ErrCircuitOpen)
+					now = now.Add(time.Minute)
+					So(cb.allow(), ShouldBeNil)
+				})
+			})
+		})
+	})
+
+	Convey("When the API is down", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		Configure(WithCircuitBreaker(2, time.Hour))
+		defer Configure(WithCircuitBreaker(0, 0))
+
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/types",
+			httpmock.NewStringResponder(503, `{"status": "503", "error":"Service unavailable"}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/formats",
+			httpmock.NewErrorResponder(errors.New("Network Issue")))
+
+		_, err := GetTypes()
+		So(err, ShouldNotEqual, ErrCircuitOpen)
+		_, err = GetFormats()
+		So(err, ShouldNotEqual, ErrCircuitOpen)
+
+		Convey("further requests should fail fast", func() {
+			httpmock.ZeroCallCounters()
+			_, err := GetSubTypes()
+			So(err, ShouldEqual, ErrCircuitOpen)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 0)
+		})
+	})
+}
+
+func Test_CircuitBreakerCanceled(t *testing.T) {
+	Convey("When requests are canceled by the caller", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		Configure(WithCircuitBreaker(2, time.Hour))
+		defer Configure(WithCircuitBreaker(0, 0))
+
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/types",
+			func(req *http.Request) (*http.Response, error) {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			})
+		for i := 0; i < 3; i++ {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := GetTypesContext(ctx)
+			So(errors.Is(err, context.Canceled), ShouldBeTrue)
+		}
+
+		Convey("the breaker should stay closed", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/types",
+				httpmock.NewStringResponder(200, `{"types":["Creature"]}`))
+			types, err := GetTypes()
+			So(err, ShouldBeNil)
+			So(types, ShouldResemble, []string{"Creature"})
+		})
+	})
+}
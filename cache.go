@@ -0,0 +1,66 @@
+package mtg
+
+import (
+	"context"
+	"sync"
+)
+
+// Cache stores responses of the API by their URL, so repeated requests can be answered without
+// the network. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the stored response of the key, if there is one.
+	Get(key string) ([]byte, bool)
+	// Set stores the response of the key.
+	Set(key string, data []byte)
+}
+
+// WithCache sets the cache used for requests whose result does not change: fetching a card by its
+// Id and fetching a set by its SetCode. Random cards and generated boosters are never cached, so
+// they stay random; queries are not cached either, since new cards are added to their results.
+// By default there is no cache; a nil cache disables it again. Use NoCache to bypass the cache
+// for single requests.
+func WithCache(cache Cache) Option {
+	return func(c *config) {
+		c.cache = cache
+	}
+}
+
+// NewMemoryCache creates a Cache which keeps all responses in memory for the lifetime of the
+// program.
+func NewMemoryCache() Cache {
+	return &memoryCache{entries: make(map[string][]byte)}
+}
+
+type memoryCache struct {
+	mu      sync.RWMutex
+	entries map[string][]byte
+}
+
+func (m *memoryCache) Get(key string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.entries[key]
+	return data, ok
+}
+
+func (m *memoryCache) Set(key string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = data
+}
+
+type noCacheKey struct{}
+
+// NoCache returns a context which makes requests bypass the cache set with WithCache, for
+// example to refresh a card: CardId(id).FetchContext(NoCache(ctx)). The fresh response is
+// still stored in the cache.
+func NoCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// cacheFor returns the configured cache and whether cached responses may be used for requests
+// with ctx. The cache is nil if there is none.
+func cacheFor(ctx context.Context) (cache Cache, read bool) {
+	bypass, _ := ctx.Value(noCacheKey{}).(bool)
+	return currentConfig().cache, !bypass
+}
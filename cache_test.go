@@ -0,0 +1,113 @@
+package mtg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Cache(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching cards with a cache", t, func() {
+		cache := NewMemoryCache()
+		Configure(WithCache(cache))
+		defer Configure(WithCache(nil))
+
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards/2633",
+			httpmock.NewStringResponder(200, `{"card":{"name":"Karplusan Yeti","set":"ICE","id":"a"}}`))
+		httpmock.ZeroCallCounters()
+
+		Convey("repeated fetches should be answered by the cache", func() {
+			card, err := MultiverseId(2633).Fetch()
+			So(err, ShouldBeNil)
+			So(card.Name, ShouldEqual, "Karplusan Yeti")
+
+			card, err = MultiverseId(2633).Fetch()
+			So(err, ShouldBeNil)
+			So(card.Name, ShouldEqual, "Karplusan Yeti")
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 1)
+		})
+
+		Convey("the cache should be bypassed if asked for", func() {
+			_, err := MultiverseId(2633).Fetch()
+			So(err, ShouldBeNil)
+
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards/2633",
+				httpmock.NewStringResponder(200, `{"card":{"name":"Karplusan Yeti","text":"updated","set":"ICE","id":"a"}}`))
+			card, err := MultiverseId(2633).FetchContext(NoCache(context.Background()))
+			So(err, ShouldBeNil)
+			So(card.Text, ShouldEqual, "updated")
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 2)
+
+			Convey("and the fresh card should be cached", func() {
+				card, err := MultiverseId(2633).Fetch()
+				So(err, ShouldBeNil)
+				So(card.Text, ShouldEqual, "updated")
+				So(httpmock.GetTotalCallCount(), ShouldEqual, 2)
+			})
+		})
+
+		Convey("missing cards should not be cached", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards/1",
+				httpmock.NewStringResponder(404, `{"status":"404","error":"Not Found"}`))
+			_, err := MultiverseId(1).Fetch()
+			So(err, ShouldNotBeNil)
+			_, ok := cache.Get("https://api.magicthegathering.io/v1/cards/1")
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
+
+func Test_CacheRandom(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching with a cache", t, func() {
+		cache := NewMemoryCache()
+		Configure(WithCache(cache))
+		defer Configure(WithCache(nil))
+
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK",
+			httpmock.NewStringResponder(200, `{"set":{"code":"KTK","name":"Khans of Tarkir","type":"expansion"}}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK/booster",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Mardu Heart-Piercer","set":"KTK","id":"a"}]}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?pageSize=1&random=true",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"b"}]}`))
+		httpmock.ZeroCallCounters()
+
+		Convey("sets should be cached", func() {
+			for i := 0; i < 2; i++ {
+				set, err := SetCode("KTK").Fetch()
+				So(err, ShouldBeNil)
+				So(set.Name, ShouldEqual, "Khans of Tarkir")
+			}
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 1)
+		})
+
+		Convey("random cards should bypass the cache", func() {
+			for i := 0; i < 2; i++ {
+				cards, err := NewQuery().Random(1)
+				So(err, ShouldBeNil)
+				So(cards, ShouldHaveLength, 1)
+			}
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 2)
+			_, ok := cache.Get("https://api.magicthegathering.io/v1/cards?pageSize=1&random=true")
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("boosters should bypass the cache", func() {
+			for i := 0; i < 2; i++ {
+				cards, err := SetCode("KTK").GenerateBooster()
+				So(err, ShouldBeNil)
+				So(cards, ShouldHaveLength, 1)
+			}
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 2)
+			_, ok := cache.Get("https://api.magicthegathering.io/v1/sets/KTK/booster")
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
@@ -1,23 +1,55 @@
 package mtg
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 // Date which can be unmarshalled from json
 type Date time.Time
 
-// ServerError is an error implementation for server messages.
+// ErrCardNotFound is returned when fetching a card by an Id which does not exist.
+var ErrCardNotFound = errors.New("card not found")
+
+// ErrInvalidId is returned by ParseId for strings which are no card id.
+var ErrInvalidId = errors.New("invalid card id")
+
+// ErrInvalidCard is returned by Card.Validate for incomplete cards.
+var ErrInvalidCard = errors.New("invalid card")
+
+// ErrNoSet is returned by Card.FetchSet for cards without a set code.
+var ErrNoSet = errors.New("card has no set")
+
+// ErrSetNotFound is returned when fetching a set by a SetCode which does not exist.
+var ErrSetNotFound = errors.New("set not found")
+
+// ErrInvalidQuery is returned when the API rejects the parameters of a request, like an
+// unknown color given to Where. The ServerError wrapping it carries the explanation of the API.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// ServerError is an error implementation for server messages. It is returned whenever the API
+// answers with an error status, usually with a JSON body like {"status":"400","error":"..."}; use
+// errors.Is with ErrInvalidQuery, ErrCardNotFound or ErrSetNotFound to check for specific
+// failures.
 type ServerError struct {
 	// Status code given by the server
 	Status string `json:"status"`
-	// Message given by the server
+	// Message given by the server, or the HTTP status line if the body had no message
 	Message string `json:"error"`
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+	// notFound is the sentinel of a 404 answered for a card or set lookup
+	notFound error
 }
 
 // Error implements the error interface
@@ -25,11 +57,57 @@ func (se ServerError) Error() string {
 	return se.Message
 }
 
+// Unwrap returns the sentinel error the StatusCode stands for: ErrInvalidQuery for 400 (Bad
+// Request). A 404 (Not Found) wraps ErrCardNotFound when fetching a card and ErrSetNotFound when
+// fetching a set, as the API answers it for both. Other errors wrap no sentinel.
+func (se ServerError) Unwrap() error {
+	switch se.StatusCode {
+	case http.StatusBadRequest:
+		return ErrInvalidQuery
+	case http.StatusNotFound:
+		return se.notFound
+	}
+	return nil
+}
+
 // Id interface for different card id types such as MultiverseId or CardId
 type Id interface {
+	// Fetch returns the card represented by the Id. If a cache is configured with WithCache,
+	// cards which were fetched before are returned from the cache.
 	Fetch() (*Card, error)
 }
 
+// FetchContext returns the card represented by id. The request is aborted when ctx is done. The
+// ids of this package are fetched with their FetchContext method; other implementations of Id
+// are fetched with their Fetch method, which does not know about ctx.
+func FetchContext(ctx context.Context, id Id) (*Card, error) {
+	if f, ok := id.(interface {
+		FetchContext(ctx context.Context) (*Card, error)
+	}); ok {
+		return f.FetchContext(ctx)
+	}
+	return id.Fetch()
+}
+
+// Exists reports whether there is a card with the id, for example to validate an id pasted by a
+// user. For the ids of this package the response is not decoded, which is cheaper than Fetch,
+// and the request is aborted when ctx is done. Other implementations of Id are fetched with
+// their Fetch method, and a card which is not found (ErrCardNotFound) does not exist.
+func Exists(ctx context.Context, id Id) (bool, error) {
+	if e, ok := id.(interface {
+		existsContext(ctx context.Context) (bool, error)
+	}); ok {
+		return e.existsContext(ctx)
+	}
+	if _, err := id.Fetch(); err != nil {
+		if errors.Is(err, ErrCardNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 // MultiverseId which can be used to fetch the card by its id
 type MultiverseId uint32
 
@@ -70,7 +148,9 @@ type Card struct {
 	Names []string `json:"names"`
 	// The mana cost of this card. Consists of one or more mana symbols. (use cmc and colors to query)
 	ManaCost string `json:"manaCost"`
-	// Converted mana cost. Always a number.
+	// Converted mana cost. Always a number. It is a float64 since a few cards have half mana
+	// costs, like Little Girl with a cmc of 0.5; whole costs like 2 and 2.0 decode to the same
+	// value. Use CMCString to display it.
 	CMC float64 `json:"cmc"`
 	// The card colors. Usually this is derived from the casting cost, but some cards are special (like the back of dual sided cards and Ghostfire).
 	Colors []string `json:"colors"`
@@ -154,19 +234,30 @@ func (d *Date) UnmarshalJSON(data []byte) (err error) {
 		return err
 	}
 
+	t, err := parseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = Date(t)
+	return nil
+}
+
+// parseDate parses a (partial) date like YYYY-MM-DD, YYYY-MM or YYYY.
+func parseDate(s string) (time.Time, error) {
 	layouts := []string{
 		"2006-01-02", "2006-01", "2006",
 	}
-	err = nil
-	var t time.Time
 	for _, layout := range layouts {
-		t, err = time.Parse(layout, s)
-		if err == nil {
-			*d = Date(t)
-			return nil
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
 		}
 	}
-	return fmt.Errorf("%q is no valid date", s)
+	return time.Time{}, fmt.Errorf("%q is no valid date", s)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The Date is encoded as YYYY-MM-DD
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Time(d).Format("2006-01-02"))
 }
 
 // String returns the string representation of the card. Containing the cardname and the id
@@ -174,22 +265,230 @@ func (c *Card) String() string {
 	return fmt.Sprintf("%s (%s)", c.Name, c.Id)
 }
 
-type cardResponse struct {
-	Card  *Card   `json:"card"`
-	Cards []*Card `json:"cards"`
+// Summary returns a readable one line summary of the card like
+// "Lightning Bolt {R} — Instant (LEA)". The mana cost is left out if the card has none.
+func (c *Card) Summary() string {
+	if c.ManaCost == "" {
+		return fmt.Sprintf("%s — %s (%s)", c.Name, c.Type, c.Set)
+	}
+	return fmt.Sprintf("%s %s — %s (%s)", c.Name, c.ManaCost, c.Type, c.Set)
+}
+
+// CMCString returns the converted mana cost of the card without a fraction if it is whole, like
+// "2" instead of "2.0", and with its fraction otherwise, like "0.5".
+func (c *Card) CMCString() string {
+	return strconv.FormatFloat(c.CMC, 'f', -1, 64)
+}
+
+// Validate checks that the card has the fields every complete record of the API has: a name and
+// the code of its set. The returned error wraps ErrInvalidCard and names the missing fields.
+// Validation is optional; cards are never validated when they are fetched.
+func (c *Card) Validate() error {
+	var missing []string
+	if strings.TrimSpace(c.Name) == "" {
+		missing = append(missing, "name")
+	}
+	if strings.TrimSpace(string(c.Set)) == "" {
+		missing = append(missing, "set")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInvalidCard, strings.Join(missing, " and "))
+	}
+	return nil
+}
+
+// OtherFaces fetches the other faces of a card with several faces, like the back of a
+// double-faced card or the other half of a split card. The API returns each face as a card of
+// its own; the faces are linked by Names and are looked up by name within the set of the card.
+// The faces are returned in the order of Names. Cards with a single face have no other faces.
+func (c *Card) OtherFaces() ([]*Card, error) {
+	if len(c.Names) <= 1 {
+		return nil, nil
+	}
+	var faces []*Card
+	for _, name := range c.Names {
+		if MatchName(name, c.Name) {
+			continue
+		}
+		cards, err := NewQuery().WhereName(name, MatchExact).Where(CardSet, string(c.Set)).All()
+		if err != nil {
+			return nil, err
+		}
+		if len(cards) == 0 {
+			return nil, fmt.Errorf("%w: no face %q of %s in set %s", ErrCardNotFound, name, c.Name, c.Set)
+		}
+		faces = append(faces, cards[0])
+	}
+	return faces, nil
 }
 
+// PrintingCodes returns the codes of all sets the card was printed in. If the card has no
+// printings, like some cards printed in a single set, the set of the card is returned.
+func (c *Card) PrintingCodes() []SetCode {
+	if len(c.Printings) > 0 {
+		return c.Printings
+	}
+	if c.Set != "" {
+		return []SetCode{c.Set}
+	}
+	return nil
+}
+
+// FetchPrintings fetches all sets the card was printed in (see PrintingCodes). The sets are
+// fetched like FetchSets, so the error may be a SetErrors.
+func (c *Card) FetchPrintings() ([]*Set, error) {
+	return c.FetchPrintingsContext(context.Background())
+}
+
+// FetchPrintingsContext works like FetchPrintings. The requests are aborted when ctx is done.
+func (c *Card) FetchPrintingsContext(ctx context.Context) ([]*Set, error) {
+	return FetchSetsContext(ctx, c.PrintingCodes()...)
+}
+
+// FetchSet fetches the set the card belongs to. Like SetCode.Fetch it uses the cache configured
+// with WithCache; to resolve the sets of many cards without requests, look them up in a
+// SetRegistry instead. ErrNoSet is returned if the card has no set code, and the error of an
+// unknown set code names the card.
+func (c *Card) FetchSet() (*Set, error) {
+	return c.FetchSetContext(context.Background())
+}
+
+// FetchSetContext works like FetchSet. The request is aborted when ctx is done.
+func (c *Card) FetchSetContext(ctx context.Context) (*Set, error) {
+	code := SetCode(strings.TrimSpace(string(c.Set)))
+	if code == "" {
+		return nil, fmt.Errorf("%w: %q", ErrNoSet, c.Name)
+	}
+	set, err := code.FetchContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("set %s of card %q: %w", code, c.Name, err)
+	}
+	return set, nil
+}
+
+// LoadCardsFromReader decodes cards which were stored before, for example a downloaded dump to
+// work offline or test data. r may contain a JSON array of cards or an object in the shape of an
+// API response ({"cards":[...]}, like a page of Query.RawAll). The cards are decoded one by
+// one, so large files are not buffered completely.
+func LoadCardsFromReader(r io.Reader) ([]*Card, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(b[0])) {
+			break
+		}
+		br.ReadByte()
+	}
+	if b, _ := br.Peek(1); b[0] == '{' {
+		return decodeCards(br)
+	}
+	return decodeCardList(json.NewDecoder(br))
+}
+
+// decodeCards decodes a response containing either a single card ({"card":{...}}) or a list of
+// cards ({"cards":[...]}). The list is decoded card by card, so the decoder does not need to
+// buffer the whole response.
 func decodeCards(reader io.Reader) ([]*Card, error) {
-	cr := new(cardResponse)
 	decoder := json.NewDecoder(reader)
-	err := decoder.Decode(&cr)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, err
+	}
+
+	var card *Card
+	var cards []*Card
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "card":
+			if card, err = decodeCard(decoder); err != nil {
+				return nil, err
+			}
+		case "cards":
+			if cards, err = decodeCardList(decoder); err != nil {
+				return nil, err
+			}
+		default:
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := expectDelim(decoder, '}'); err != nil {
+		return nil, err
+	}
+
+	if card != nil {
+		return []*Card{card}, nil
+	}
+	return cards, nil
+}
+
+func decodeCardList(decoder *json.Decoder) ([]*Card, error) {
+	tok, err := decoder.Token()
 	if err != nil {
 		return nil, err
 	}
-	if cr.Card != nil {
-		return []*Card{cr.Card}, nil
+	if tok == nil {
+		return nil, nil
+	}
+	if tok != json.Delim('[') {
+		return nil, fmt.Errorf("expected a list of cards but got %v", tok)
+	}
+
+	var cards []*Card
+	for decoder.More() {
+		card, err := decodeCard(decoder)
+		if err != nil {
+			return nil, err
+		}
+		if card == nil {
+			card = new(Card)
+		}
+		cards = append(cards, card)
 	}
-	return cr.Cards, nil
+	return cards, expectDelim(decoder, ']')
+}
+
+// decodeCard decodes the next card of decoder and passes it to the decode hook, if one is
+// configured. A JSON null is decoded as a nil card, which is not passed to the hook.
+func decodeCard(decoder *json.Decoder) (*Card, error) {
+	hook := currentConfig().cardDecodeHook
+	if hook == nil {
+		var card *Card
+		err := decoder.Decode(&card)
+		return card, err
+	}
+
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	var card *Card
+	if err := json.Unmarshal(raw, &card); err != nil || card == nil {
+		return nil, err
+	}
+	if err := hook(raw, card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	tok, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %v but got %v", delim, tok)
+	}
+	return nil
 }
 
 func checkError(r *http.Response) error {
@@ -197,16 +496,50 @@ func checkError(r *http.Response) error {
 		return nil
 	}
 
-	var se ServerError
-
-	if err := json.NewDecoder(r.Body).Decode(&se); err != nil {
-		return errors.New(r.Status)
+	// the status is a string in most responses but a number in some validation errors
+	var body struct {
+		Status  json.RawMessage `json:"status"`
+		Message string          `json:"error"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		// the body is no JSON, like a plain text message of a proxy
+		return ServerError{Status: strconv.Itoa(r.StatusCode), Message: r.Status, StatusCode: r.StatusCode}
+	}
+	se := ServerError{
+		Status:     strings.Trim(string(body.Status), `"`),
+		Message:    body.Message,
+		StatusCode: r.StatusCode,
+	}
+	if se.Status == "" || se.Status == "null" {
+		se.Status = strconv.Itoa(r.StatusCode)
+	}
+	if se.Message == "" {
+		se.Message = r.Status
 	}
 	return se
 }
 
-func fetchCardById(str string) (*Card, error) {
-	resp, err := http.Get(fmt.Sprintf("%scards/%s", queryUrl, str))
+// notFound marks err of a 404 response as the missing resource given by sentinel.
+func notFound(err, sentinel error) error {
+	if se, ok := err.(ServerError); ok {
+		se.notFound = sentinel
+		return se
+	}
+	return fmt.Errorf("%w: %v", sentinel, err)
+}
+
+func fetchCardById(ctx context.Context, str string) (*Card, error) {
+	url := fmt.Sprintf("%s/%s", cardsUrl(), str)
+	cache, read := cacheFor(ctx)
+	if cache != nil && read {
+		if data, ok := cache.Get(url); ok {
+			if cards, err := decodeCards(bytes.NewReader(data)); err == nil && len(cards) == 1 {
+				return cards[0], nil
+			}
+		}
+	}
+
+	resp, err := httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -214,24 +547,216 @@ func fetchCardById(str string) (*Card, error) {
 	defer bdy.Close()
 
 	if err := checkError(resp); err != nil {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, notFound(err, ErrCardNotFound)
+		}
 		return nil, err
 	}
-	cards, err := decodeCards(bdy)
+	var body io.Reader = bdy
+	var data bytes.Buffer
+	if cache != nil {
+		body = io.TeeReader(bdy, &data)
+	}
+	cards, err := decodeCards(body)
 	if err != nil {
 		return nil, err
 	}
 	if len(cards) != 1 {
-		return nil, fmt.Errorf("Card with Id %s not found", str)
+		return nil, fmt.Errorf("%w: no card with Id %s", ErrCardNotFound, str)
+	}
+	if cache != nil {
+		cache.Set(url, data.Bytes())
 	}
 	return cards[0], nil
 }
 
+// exists requests url and reports whether it was found. A response with the status 404 is
+// reported as false, all other errors are returned. The body is not decoded.
+func exists(ctx context.Context, url string) (bool, error) {
+	resp, err := httpGet(ctx, url)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err := checkError(resp); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ParseId guesses the kind of id of s, for example of an id pasted by a user: numbers are
+// returned as MultiverseId, ids of the API (40 hex digits or a UUID) as CardId. The error wraps
+// ErrInvalidId if s is neither.
+func ParseId(s string) (Id, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return MultiverseId(n), nil
+	}
+	if isCardId(s) {
+		return CardId(strings.ToLower(s)), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidId, s)
+}
+
+// isCardId reports whether s is a SHA1 hash in hex (the ids of the API) or a UUID (the ids of
+// newer cards).
+func isCardId(s string) bool {
+	switch len(s) {
+	case 40:
+	case 36:
+		for _, i := range []int{8, 13, 18, 23} {
+			if s[i] != '-' {
+				return false
+			}
+		}
+		s = strings.ReplaceAll(s, "-", "")
+	default:
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// MultiverseIdValue returns the MultiverseId of the card, which is only set for cards on
+// Gatherer. The CardId of the card is always set as Id.
+func (c *Card) MultiverseIdValue() (MultiverseId, bool) {
+	n, err := strconv.ParseUint(c.MultiverseId, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return MultiverseId(n), true
+}
+
 // Fetch returns the card represented by the MutliverseId
 func (mID MultiverseId) Fetch() (*Card, error) {
-	return fetchCardById(fmt.Sprintf("%d", mID))
+	return mID.FetchContext(context.Background())
+}
+
+// FetchContext returns the card represented by the MultiverseId. The request is aborted when ctx is done.
+func (mID MultiverseId) FetchContext(ctx context.Context) (*Card, error) {
+	return fetchCardById(ctx, fmt.Sprintf("%d", mID))
+}
+
+// multiverseIdBatchSize is the number of ids FetchByMultiverseIds requests at once. Each id takes
+// at most ten characters in the URL (including the encoded "|"), which keeps the URLs well below
+// the length the API accepts, and a batch fits into a single page of MaxPageSize cards.
+const multiverseIdBatchSize = 100
+
+// FetchByMultiverseIds fetches the cards with the given multiverse ids. Long lists are split into
+// batches which are requested one after the other, so the URLs do not get too long for the API.
+// The cards are returned in the order of the batches; ids which do not exist are left out.
+func FetchByMultiverseIds(ids []int) ([]*Card, error) {
+	return FetchByMultiverseIdsContext(context.Background(), ids, 1)
+}
+
+// FetchByMultiverseIdsContext works like FetchByMultiverseIds, but requests up to concurrency
+// batches at the same time. If a batch fails, the remaining batches are not requested and the
+// first error is returned. The requests are aborted when ctx is done.
+func FetchByMultiverseIdsContext(ctx context.Context, ids []int, concurrency int) ([]*Card, error) {
+	var batches [][]MultiverseId
+	seen := make(map[int]bool, len(ids))
+	var batch []MultiverseId
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		batch = append(batch, MultiverseId(id))
+		if len(batch) == multiverseIdBatchSize {
+			batches = append(batches, batch)
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	if len(batches) == 0 {
+		return nil, nil
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]*Card, len(batches))
+	var (
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	jobs := make(chan int)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				cards, err := NewQuery().WhereMultiverseIds(batches[i]...).(query).all(ctx, 0, false, nil)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[i] = cards
+			}
+		}()
+	}
+	for i := range batches {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var cards []*Card
+	for _, r := range results {
+		cards = append(cards, r...)
+	}
+	return cards, nil
+}
+
+// Exists reports whether there is a card with the MultiverseId.
+func (mID MultiverseId) Exists() (bool, error) {
+	return mID.existsContext(context.Background())
+}
+
+func (mID MultiverseId) existsContext(ctx context.Context) (bool, error) {
+	return exists(ctx, fmt.Sprintf("%s/%d", cardsUrl(), mID))
 }
 
 // Fetch returns the card represented by the CardId
 func (id CardId) Fetch() (*Card, error) {
-	return fetchCardById(string(id))
+	return id.FetchContext(context.Background())
+}
+
+// FetchContext returns the card represented by the CardId. The request is aborted when ctx is done.
+func (id CardId) FetchContext(ctx context.Context) (*Card, error) {
+	return fetchCardById(ctx, string(id))
+}
+
+// Exists reports whether there is a card with the CardId.
+func (id CardId) Exists() (bool, error) {
+	return id.existsContext(context.Background())
+}
+
+func (id CardId) existsContext(ctx context.Context) (bool, error) {
+	return exists(ctx, fmt.Sprintf("%s/%s", cardsUrl(), id))
 }
@@ -1,7 +1,13 @@
 package mtg
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -64,6 +70,21 @@ func Test_FetchCards(t *testing.T) {
 
 			_, ok := err.(ServerError)
 			So(ok, ShouldBeTrue)
+			So(errors.Is(err, ErrCardNotFound), ShouldBeTrue)
+		})
+
+		Convey("Fetching an empty response should report a missing card", func() {
+			card, err := CardId("noCardsInResponse").Fetch()
+			So(card, ShouldBeNil)
+			So(errors.Is(err, ErrCardNotFound), ShouldBeTrue)
+		})
+
+		Convey("Other errors should not be reported as a missing card", func() {
+			_, err := CardId("noErrorMsg").Fetch()
+			So(errors.Is(err, ErrCardNotFound), ShouldBeFalse)
+
+			_, err = MultiverseId(1).Fetch()
+			So(errors.Is(err, ErrCardNotFound), ShouldBeFalse)
 		})
 
 		Convey("Fetching a CardId", func() {
@@ -83,13 +104,417 @@ func Test_FetchCards(t *testing.T) {
 			So(card, ShouldBeNil)
 			So(err, ShouldNotBeNil)
 
-			_, ok := err.(ServerError)
-			So(ok, ShouldBeFalse)
+			se, ok := err.(ServerError)
+			So(ok, ShouldBeTrue)
+			So(se.StatusCode, ShouldEqual, 500)
+			So(se.Status, ShouldEqual, "500")
 
 			card, err = CardId("noCardsInResponse").Fetch()
 			So(card, ShouldBeNil)
 			So(err, ShouldNotBeNil)
 
 		})
+
+		Convey("Fetching with a canceled context", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards/slow",
+				func(req *http.Request) (*http.Response, error) {
+					<-req.Context().Done()
+					return nil, req.Context().Err()
+				})
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			card, err := CardId("slow").FetchContext(ctx)
+			So(card, ShouldBeNil)
+			So(errors.Is(err, context.Canceled), ShouldBeTrue)
+		})
+	})
+}
+
+func Test_CardSummary(t *testing.T) {
+	Convey("When summarizing a card", t, func() {
+		Convey("name, mana cost, type and set should be contained", func() {
+			card := &Card{Name: "Lightning Bolt", ManaCost: "{R}", Type: "Instant", Set: "LEA", Id: "a"}
+			So(card.Summary(), ShouldEqual, "Lightning Bolt {R} — Instant (LEA)")
+		})
+		Convey("cards without a mana cost should leave it out", func() {
+			card := &Card{Name: "Forest", Type: "Basic Land — Forest", Set: "LEA", Id: "b"}
+			So(card.Summary(), ShouldEqual, "Forest — Basic Land — Forest (LEA)")
+		})
+		Convey("String should still contain the name and the id", func() {
+			card := &Card{Name: "Lightning Bolt", ManaCost: "{R}", Type: "Instant", Set: "LEA", Id: "a"}
+			So(card.String(), ShouldEqual, "Lightning Bolt (a)")
+		})
+	})
+}
+
+func Test_CardCMCString(t *testing.T) {
+	Convey("When rendering the converted mana cost", t, func() {
+		Convey("whole costs should have no fraction", func() {
+			cards, err := LoadCardsFromReader(strings.NewReader(`[{"name":"Grizzly Bears","cmc":2.0},{"name":"Ornithopter","cmc":0}]`))
+			So(err, ShouldBeNil)
+			So(cards[0].CMC, ShouldEqual, 2)
+			So(cards[0].CMCString(), ShouldEqual, "2")
+			So(cards[1].CMCString(), ShouldEqual, "0")
+		})
+		Convey("half costs should keep their fraction", func() {
+			cards, err := LoadCardsFromReader(strings.NewReader(`[{"name":"Little Girl","cmc":0.5},{"name":"Half Cost","cmc":1.5}]`))
+			So(err, ShouldBeNil)
+			So(cards[0].CMCString(), ShouldEqual, "0.5")
+			So(cards[1].CMCString(), ShouldEqual, "1.5")
+		})
+	})
+}
+
+func Test_CardValidate(t *testing.T) {
+	Convey("When validating a card", t, func() {
+		Convey("a card with name and set should be valid", func() {
+			So((&Card{Name: "Lightning Bolt", Set: "LEA"}).Validate(), ShouldBeNil)
+		})
+
+		Convey("missing fields should be reported", func() {
+			err := (&Card{Name: "Lightning Bolt"}).Validate()
+			So(errors.Is(err, ErrInvalidCard), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "invalid card: missing set")
+
+			err = (&Card{Name: " "}).Validate()
+			So(errors.Is(err, ErrInvalidCard), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "invalid card: missing name and set")
+		})
+	})
+}
+
+func Test_ParseId(t *testing.T) {
+	Convey("When parsing an id", t, func() {
+		Convey("numbers should be multiverse ids", func() {
+			id, err := ParseId(" 2633 ")
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, MultiverseId(2633))
+		})
+
+		Convey("hex hashes should be card ids", func() {
+			id, err := ParseId("441CCF2C2AD92D25852284238859EF5ED556A1FE")
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, CardId("441ccf2c2ad92d25852284238859ef5ed556a1fe"))
+		})
+
+		Convey("UUIDs should be card ids", func() {
+			id, err := ParseId("5f8287b1-5bb6-5f4c-ad17-316a40d5bb0c")
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, CardId("5f8287b1-5bb6-5f4c-ad17-316a40d5bb0c"))
+		})
+
+		Convey("other strings should return ErrInvalidId", func() {
+			for _, s := range []string{"", "Lightning Bolt", "441ccf2c", "441ccf2c2ad92d25852284238859ef5ed556a1fx", "-1", "99999999999"} {
+				_, err := ParseId(s)
+				So(errors.Is(err, ErrInvalidId), ShouldBeTrue)
+			}
+		})
+	})
+
+	Convey("When reading the multiverse id of a card", t, func() {
+		id, ok := (&Card{MultiverseId: "2633"}).MultiverseIdValue()
+		So(ok, ShouldBeTrue)
+		So(id, ShouldEqual, MultiverseId(2633))
+
+		_, ok = (&Card{}).MultiverseIdValue()
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func Test_OtherFaces(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching the other faces of a card", t, func() {
+		httpmock.RegisterResponder("GET", `https://api.magicthegathering.io/v1/cards?name=%22Insectile+Aberration%22&set=ISD`,
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Insectile Aberration","names":["Delver of Secrets","Insectile Aberration"],"set":"ISD","number":"51b","id":"b"}]}`))
+
+		Convey("the back of a double-faced card should be fetched", func() {
+			card := &Card{Name: "Delver of Secrets", Names: []string{"Delver of Secrets", "Insectile Aberration"}, Set: "ISD"}
+			faces, err := card.OtherFaces()
+			So(err, ShouldBeNil)
+			So(faces, ShouldHaveLength, 1)
+			So(faces[0].Name, ShouldEqual, "Insectile Aberration")
+		})
+
+		Convey("single-faced cards should have no other faces", func() {
+			faces, err := (&Card{Name: "Lightning Bolt", Set: "LEA"}).OtherFaces()
+			So(err, ShouldBeNil)
+			So(faces, ShouldBeEmpty)
+		})
+
+		Convey("missing faces should return ErrCardNotFound", func() {
+			httpmock.RegisterResponder("GET", `https://api.magicthegathering.io/v1/cards?name=%22Insectile+Aberration%22&set=ISD`,
+				httpmock.NewStringResponder(200, `{"cards":[]}`))
+			card := &Card{Name: "Delver of Secrets", Names: []string{"Delver of Secrets", "Insectile Aberration"}, Set: "ISD"}
+			_, err := card.OtherFaces()
+			So(errors.Is(err, ErrCardNotFound), ShouldBeTrue)
+		})
+	})
+}
+
+func Test_LoadCardsFromReader(t *testing.T) {
+	Convey("When loading cards from a reader", t, func() {
+		Convey("a list of cards should be decoded", func() {
+			cards, err := LoadCardsFromReader(strings.NewReader(`
+				[{"name":"Lightning Bolt","set":"LEA","id":"a"},{"name":"Shock","set":"M19","id":"b"}]`))
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(cards, ShouldContainCard, "Shock")
+		})
+
+		Convey("an API response should be decoded", func() {
+			cards, err := LoadCardsFromReader(strings.NewReader(`{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`))
+			So(err, ShouldBeNil)
+			So(cards, ShouldContainCard, "Lightning Bolt")
+		})
+
+		Convey("invalid data should return an error", func() {
+			_, err := LoadCardsFromReader(strings.NewReader(`"cards"`))
+			So(err, ShouldNotBeNil)
+
+			_, err = LoadCardsFromReader(strings.NewReader(`[{"name":}]`))
+			So(err, ShouldNotBeNil)
+
+			_, err = LoadCardsFromReader(strings.NewReader(``))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_FetchPrintings(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching the printings of a card", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/LEA",
+			httpmock.NewStringResponder(200, `{"set":{"code":"LEA","name":"Limited Edition Alpha","type":"core","releaseDate":"1993-08-05"}}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/M10",
+			httpmock.NewStringResponder(200, `{"set":{"code":"M10","name":"Magic 2010","type":"core","releaseDate":"2009-07-17"}}`))
+
+		Convey("all printed sets should be fetched in order", func() {
+			card := &Card{Name: "Lightning Bolt", Set: "M10", Printings: []SetCode{"LEA", "M10"}}
+			sets, err := card.FetchPrintings()
+			So(err, ShouldBeNil)
+			So(sets, ShouldHaveLength, 2)
+			So(sets[0].Name, ShouldEqual, "Limited Edition Alpha")
+			So(sets[1].Name, ShouldEqual, "Magic 2010")
+		})
+
+		Convey("a card without printings should use its own set", func() {
+			card := &Card{Name: "Lightning Bolt", Set: "LEA"}
+			So(card.PrintingCodes(), ShouldResemble, []SetCode{"LEA"})
+
+			sets, err := card.FetchPrintings()
+			So(err, ShouldBeNil)
+			So(sets, ShouldHaveLength, 1)
+			So(sets[0].Name, ShouldEqual, "Limited Edition Alpha")
+		})
+
+		Convey("a card without any set should have no printings", func() {
+			card := &Card{Name: "Lightning Bolt"}
+			So(card.PrintingCodes(), ShouldBeEmpty)
+		})
+	})
+}
+
+func Test_FetchByMultiverseIds(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching cards by multiverse ids", t, func() {
+		var batchSizes []int
+		var mu sync.Mutex
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards",
+			func(req *http.Request) (*http.Response, error) {
+				ids := strings.Split(req.URL.Query().Get("multiverseid"), "|")
+				mu.Lock()
+				batchSizes = append(batchSizes, len(ids))
+				mu.Unlock()
+				cards := make([]string, len(ids))
+				for i, id := range ids {
+					cards[i] = fmt.Sprintf(`{"name":"Card %s","set":"TST","multiverseid":"%s","id":"%s"}`, id, id, id)
+				}
+				return httpmock.NewStringResponse(200, `{"cards":[`+strings.Join(cards, ",")+`]}`), nil
+			})
+
+		ids := make([]int, 250)
+		for i := range ids {
+			ids[i] = i + 1
+		}
+
+		Convey("a list exceeding one batch should be split into several requests", func() {
+			cards, err := FetchByMultiverseIds(ids)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 250)
+			So(cards[0].Name, ShouldEqual, "Card 1")
+			So(cards[249].Name, ShouldEqual, "Card 250")
+			So(batchSizes, ShouldResemble, []int{100, 100, 50})
+		})
+
+		Convey("batches should be fetched concurrently if asked for", func() {
+			cards, err := FetchByMultiverseIdsContext(context.Background(), ids, 3)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 250)
+			So(cards[100].Name, ShouldEqual, "Card 101")
+			So(batchSizes, ShouldHaveLength, 3)
+		})
+
+		Convey("duplicate and invalid ids should be skipped", func() {
+			cards, err := FetchByMultiverseIds([]int{3, 0, 3, -1, 5})
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(batchSizes, ShouldResemble, []int{2})
+
+			cards, err = FetchByMultiverseIds(nil)
+			So(err, ShouldBeNil)
+			So(cards, ShouldBeEmpty)
+		})
+
+		Convey("a failing batch should return an error", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards",
+				httpmock.NewStringResponder(500, `{"status":500,"error":"Internal Server Error"}`))
+			_, err := FetchByMultiverseIdsContext(context.Background(), ids, 2)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_CardExists(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When checking whether a card exists", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards/2633",
+			httpmock.NewStringResponder(200, `{"card":{"name":"Karplusan Yeti","set":"ICE","id":"a"}}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards/abc",
+			httpmock.NewStringResponder(404, `{"status":"404","error":"Not Found"}`))
+
+		Convey("existing cards should be found", func() {
+			ok, err := MultiverseId(2633).Exists()
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+		})
+		Convey("missing cards should not be found without an error", func() {
+			ok, err := CardId("abc").Exists()
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+		})
+		Convey("other errors should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards/2633",
+				httpmock.NewStringResponder(500, `{"status":"500","error":"Internal Server Error"}`))
+			ok, err := MultiverseId(2633).Exists()
+			So(err, ShouldNotBeNil)
+			So(ok, ShouldBeFalse)
+		})
+		Convey("any Id should be checked with Exists", func() {
+			ok, err := Exists(context.Background(), MultiverseId(2633))
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+			ok, err = Exists(context.Background(), CardId("abc"))
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+		})
+		Convey("other ids should be checked with Fetch", func() {
+			ok, err := Exists(context.Background(), customId("Lightning Bolt"))
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+			ok, err = Exists(context.Background(), customId(""))
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
+
+// customId is an Id implemented outside of the package.
+type customId string
+
+func (id customId) Fetch() (*Card, error) {
+	if id == "" {
+		return nil, ErrCardNotFound
+	}
+	return &Card{Name: string(id)}, nil
+}
+
+func largeCardsFixture(n int) []byte {
+	card := `{"name":"Karplusan Yeti","manaCost":"{3}{R}{R}","cmc":5.0,"colors":["Red"],"colorIdentity":["R"],"type":"Creature — Yeti","types":["Creature"],"subtypes":["Yeti"],"rarity":"Rare","set":"ICE","setName":"Ice Age","text":"{T}: Karplusan Yeti deals damage equal to its power to target creature. That creature deals damage equal to its power to Karplusan Yeti.","artist":"Quinton Hoover","number":"194","power":"3","toughness":"3","layout":"normal","printings":["ICE","ME2"],"legalities":[{"format":"Legacy","legality":"Legal"},{"format":"Vintage","legality":"Legal"}],"id":"ab64a1dd4e0ec7eb8a7b7e4ba5f1d2e3b4c5d6e7"}`
+	var buf bytes.Buffer
+	buf.WriteString(`{"cards":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(card)
+	}
+	buf.WriteString(`]}`)
+	return buf.Bytes()
+}
+
+func Benchmark_DecodeCards(b *testing.B) {
+	data := largeCardsFixture(5000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeCards(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Test_FetchSet(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching the set of a card", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/PLS",
+			httpmock.NewStringResponder(200, `{"set":{"code":"PLS","name":"Planeshift","type":"expansion","releaseDate":"2001-02-05"}}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/XXX",
+			httpmock.NewStringResponder(404, `{"status":"404","error":"Not Found"}`))
+
+		Convey("the set of the card should be returned", func() {
+			set, err := (&Card{Name: "Flametongue Kavu", Set: "PLS"}).FetchSet()
+			So(err, ShouldBeNil)
+			So(set.Name, ShouldEqual, "Planeshift")
+		})
+
+		Convey("a card without set code should return ErrNoSet", func() {
+			_, err := (&Card{Name: "Flametongue Kavu"}).FetchSet()
+			So(errors.Is(err, ErrNoSet), ShouldBeTrue)
+		})
+
+		Convey("an unknown set code should name the card", func() {
+			_, err := (&Card{Name: "Flametongue Kavu", Set: "XXX"}).FetchSet()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, `"Flametongue Kavu"`)
+			var se ServerError
+			So(errors.As(err, &se), ShouldBeTrue)
+		})
+	})
+}
+
+func Test_FetchContext(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching any Id with a context", t, func() {
+		Convey("the ids of the package should use the context", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards/slow",
+				func(req *http.Request) (*http.Response, error) {
+					<-req.Context().Done()
+					return nil, req.Context().Err()
+				})
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := FetchContext(ctx, CardId("slow"))
+			So(errors.Is(err, context.Canceled), ShouldBeTrue)
+		})
+
+		Convey("other ids should be fetched with Fetch", func() {
+			card, err := FetchContext(context.Background(), customId("Lightning Bolt"))
+			So(err, ShouldBeNil)
+			So(card.Name, ShouldEqual, "Lightning Bolt")
+		})
 	})
 }
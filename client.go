@@ -0,0 +1,299 @@
+package mtg
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is the time a request to the API may take (including reading the response)
+// unless configured otherwise with WithTimeout.
+const DefaultTimeout = 30 * time.Second
+
+const (
+	// DefaultPageSize is the number of cards per page used by Query.Page and Query.All unless
+	// configured otherwise with WithPageSize.
+	DefaultPageSize = 100
+	// MaxPageSize is the largest page size supported by the API.
+	MaxPageSize = 100
+)
+
+type config struct {
+	baseURL   string
+	cardsPath string
+	setsPath  string
+	timeout   time.Duration
+	pageSize  int
+	client    *http.Client
+	breaker   *circuitBreaker
+	retry     RetryPolicy
+	cache     Cache
+	baseCtx   context.Context
+
+	requestHook  func(RequestInfo)
+	responseHook func(*http.Response)
+	pageLogger   *log.Logger
+
+	cardDecodeHook func(json.RawMessage, *Card) error
+}
+
+// Option changes how requests to the API are made. Options are applied with Configure.
+type Option func(*config)
+
+var (
+	cfgMu sync.RWMutex
+	cfg   = defaultConfig()
+)
+
+func defaultConfig() config {
+	return config{
+		baseURL:   DefaultBaseURL,
+		cardsPath: DefaultCardsPath,
+		setsPath:  DefaultSetsPath,
+		timeout:   DefaultTimeout,
+		pageSize:  DefaultPageSize,
+	}
+}
+
+// Configure applies the given options to all requests started afterwards.
+func Configure(opts ...Option) {
+	cfgMu.Lock()
+	defer cfgMu.Unlock()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+}
+
+func currentConfig() config {
+	cfgMu.RLock()
+	defer cfgMu.RUnlock()
+	return cfg
+}
+
+func baseUrl() string {
+	return currentConfig().baseURL
+}
+
+// cardsUrl returns the URL of the cards endpoint without a trailing slash.
+func cardsUrl() string {
+	c := currentConfig()
+	return c.baseURL + c.cardsPath
+}
+
+// setsUrl returns the URL of the sets endpoint without a trailing slash.
+func setsUrl() string {
+	c := currentConfig()
+	return c.baseURL + c.setsPath
+}
+
+// WithBaseURL sets the URL of the API, for example to use a mirror or a test server.
+// The default is DefaultBaseURL.
+func WithBaseURL(url string) Option {
+	if !strings.HasSuffix(url, "/") {
+		url += "/"
+	}
+	return func(c *config) {
+		c.baseURL = url
+	}
+}
+
+// WithEndpointPaths sets the paths of the cards and sets endpoints relative to the base URL, for
+// example to adapt to a new version of the API which renamed them. The defaults are
+// DefaultCardsPath and DefaultSetsPath; an empty path restores the default.
+func WithEndpointPaths(cards, sets string) Option {
+	cards, sets = strings.Trim(cards, "/"), strings.Trim(sets, "/")
+	if cards == "" {
+		cards = DefaultCardsPath
+	}
+	if sets == "" {
+		sets = DefaultSetsPath
+	}
+	return func(c *config) {
+		c.cardsPath = cards
+		c.setsPath = sets
+	}
+}
+
+// WithTimeout sets the time a request may take. The timeout is applied in addition to any
+// deadline of the context given to a request. A timeout of zero disables it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// WithPageSize sets the number of cards per page used by Query.Page and Query.All. Sizes above
+// MaxPageSize are reduced to MaxPageSize since the API does not return bigger pages, a size of
+// zero or less restores DefaultPageSize.
+func WithPageSize(size int) Option {
+	switch {
+	case size <= 0:
+		size = DefaultPageSize
+	case size > MaxPageSize:
+		size = MaxPageSize
+	}
+	return func(c *config) {
+		c.pageSize = size
+	}
+}
+
+// WithHTTPClient sets the client used for all requests, for example to tune the keep-alive
+// settings of its Transport (MaxIdleConnsPerHost, IdleConnTimeout, ...). A nil client restores
+// http.DefaultClient, which is used by default. Responses are always read completely before
+// they are closed, so the fetched pages of a query reuse the same connection instead of
+// starting a new TLS handshake for each page.
+//
+// http.DefaultClient uses http.DefaultTransport, which honors the proxy settings of the
+// environment (HTTP_PROXY, HTTPS_PROXY and NO_PROXY, see http.ProxyFromEnvironment), so no
+// custom client is needed behind a proxy. A client with its own Transport only uses a proxy if
+// its Proxy field is set; set it to http.ProxyFromEnvironment to keep honoring the environment.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithBaseContext sets a context all requests are bound to, in addition to the context given to
+// a single call, for example the context of a service which is canceled on shutdown. A request
+// is aborted as soon as either context is done; deadlines of both apply. Values are only taken
+// from the context of the call. A nil context removes the base context.
+func WithBaseContext(ctx context.Context) Option {
+	return func(c *config) {
+		c.baseCtx = ctx
+	}
+}
+
+// withBaseContext returns a context which is done when ctx or base is done.
+func withBaseContext(ctx, base context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-base.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// maxDrain is the maximum number of unread bytes which are discarded when a response body is
+// closed. Bigger rests are not worth reading; the connection is closed instead.
+const maxDrain = 4 << 10
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelBody) Close() error {
+	// the connection can only be reused if the body was read to its end
+	io.CopyN(io.Discard, b.ReadCloser, maxDrain)
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func httpGet(ctx context.Context, url string) (*http.Response, error) {
+	c := currentConfig()
+	for attempt := 1; ; attempt++ {
+		resp, err := tryGet(ctx, c, url)
+		if c.retry == nil {
+			return resp, err
+		}
+		retry, delay := c.retry.ShouldRetry(resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		var baseDone <-chan struct{}
+		if c.baseCtx != nil {
+			baseDone = c.baseCtx.Done()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-baseDone:
+			timer.Stop()
+			return nil, c.baseCtx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// tryGet makes a single attempt of a request, guarded by the circuit breaker if there is one.
+func tryGet(ctx context.Context, c config, url string) (*http.Response, error) {
+	if c.breaker == nil {
+		return doGet(ctx, c, url)
+	}
+
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := doGet(ctx, c, url)
+	if ctx.Err() != nil {
+		// the caller gave up, which says nothing about the health of the API
+		c.breaker.release()
+		return resp, err
+	}
+	c.breaker.record(err == nil && resp.StatusCode < 500)
+	return resp, err
+}
+
+func doGet(ctx context.Context, c config, url string) (*http.Response, error) {
+	cancel := context.CancelFunc(func() {})
+	if c.baseCtx != nil {
+		ctx, cancel = withBaseContext(ctx, c.baseCtx)
+	}
+	if c.timeout > 0 {
+		cancelBase := cancel
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, c.timeout)
+		cancel = func() {
+			cancelTimeout()
+			cancelBase()
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	client := c.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	start := time.Now()
+	var resp *http.Response
+	if c.requestHook != nil {
+		resp, err = doHooked(c, client, req.WithContext(ctx))
+	} else {
+		resp, err = client.Do(req.WithContext(ctx))
+	}
+	if info := debugInfoFrom(ctx); info != nil {
+		r := DebugRequest{URL: url, Params: req.URL.Query(), Duration: time.Since(start), Err: err}
+		if resp != nil {
+			r.StatusCode = resp.StatusCode
+		}
+		info.record(r)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelBody{resp.Body, cancel}
+	if c.responseHook != nil {
+		c.responseHook(resp)
+	}
+	return resp, nil
+}
@@ -0,0 +1,231 @@
+package mtg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Timeout(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When the server does not respond", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/types",
+			func(req *http.Request) (*http.Response, error) {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			})
+
+		Convey("the default timeout should be used", func() {
+			So(currentConfig().timeout, ShouldEqual, DefaultTimeout)
+		})
+
+		Convey("the request should be aborted after the configured timeout", func() {
+			Configure(WithTimeout(10 * time.Millisecond))
+			defer Configure(WithTimeout(DefaultTimeout))
+
+			_, err := GetTypes()
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+		})
+	})
+}
+
+func Test_BaseContext(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When a base context is configured", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/types",
+			func(req *http.Request) (*http.Response, error) {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			})
+		base, cancelBase := context.WithCancel(context.Background())
+		Configure(WithBaseContext(base))
+		defer Configure(WithBaseContext(nil))
+
+		Convey("canceling the base context should abort requests", func() {
+			time.AfterFunc(10*time.Millisecond, cancelBase)
+			_, err := GetTypesContext(context.Background())
+			So(errors.Is(err, context.Canceled), ShouldBeTrue)
+		})
+
+		Convey("canceling the context of the call should still abort the request", func() {
+			defer cancelBase()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			_, err := GetTypesContext(ctx)
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+			So(base.Err(), ShouldBeNil)
+		})
+
+		Convey("requests should succeed while both contexts are alive", func() {
+			defer cancelBase()
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/types",
+				httpmock.NewStringResponder(200, `{"types":["Creature"]}`))
+			types, err := GetTypes()
+			So(err, ShouldBeNil)
+			So(types, ShouldResemble, []string{"Creature"})
+		})
+	})
+}
+
+func Test_EndpointPaths(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When the endpoint paths are configured", t, func() {
+		Configure(WithBaseURL("https://api.magicthegathering.io/"), WithEndpointPaths("/v2/cards/", "v2/sets"))
+		defer Configure(WithBaseURL(DefaultBaseURL), WithEndpointPaths("", ""))
+
+		Convey("the URLs should use the paths", func() {
+			So(NewQuery().Where(CardName, "Bolt").URL(), ShouldEqual, "https://api.magicthegathering.io/v2/cards?name=Bolt")
+			So(NewSetQuery().Where(SetName, "Alpha").URL(), ShouldEqual, "https://api.magicthegathering.io/v2/sets?name=Alpha")
+		})
+
+		Convey("cards and sets should be fetched from the paths", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v2/cards/abc",
+				httpmock.NewStringResponder(200, `{"card":{"name":"Lightning Bolt","set":"LEA","id":"abc"}}`))
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v2/sets/LEA",
+				httpmock.NewStringResponder(200, `{"set":{"code":"LEA","name":"Limited Edition Alpha"}}`))
+
+			card, err := CardId("abc").Fetch()
+			So(err, ShouldBeNil)
+			So(card.Name, ShouldEqual, "Lightning Bolt")
+			set, err := SetCode("LEA").Fetch()
+			So(err, ShouldBeNil)
+			So(set.Name, ShouldEqual, "Limited Edition Alpha")
+		})
+	})
+
+	Convey("By default the paths of the v1 API should be used", t, func() {
+		So(NewQuery().URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?")
+		So(NewSetQuery().URL(), ShouldEqual, "https://api.magicthegathering.io/v1/sets?")
+	})
+}
+
+func Test_PageSize(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When configuring the page size", t, func() {
+		defer Configure(WithPageSize(DefaultPageSize))
+
+		Convey("the default page size should be used", func() {
+			So(currentConfig().pageSize, ShouldEqual, DefaultPageSize)
+		})
+
+		Convey("sizes above the maximum should be reduced", func() {
+			Configure(WithPageSize(1000))
+			So(currentConfig().pageSize, ShouldEqual, MaxPageSize)
+		})
+
+		Convey("invalid sizes should restore the default", func() {
+			Configure(WithPageSize(10), WithPageSize(-1))
+			So(currentConfig().pageSize, ShouldEqual, DefaultPageSize)
+		})
+
+		Convey("Page and All should use the configured size", func() {
+			Configure(WithPageSize(10))
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=10",
+				httpmock.NewStringResponder(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`))
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&pageSize=10",
+				httpmock.NewStringResponder(200, `{"cards":[{"name":"Chain Lightning","set":"LEG","id":"b"}]}`))
+
+			cards, _, err := NewQuery().Where(CardName, "Bolt").Page(1)
+			So(err, ShouldBeNil)
+			So(cards, ShouldContainCard, "Lightning Bolt")
+
+			cards, err = NewQuery().Where(CardName, "Bolt").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldContainCard, "Chain Lightning")
+		})
+	})
+}
+
+type recordingTransport struct {
+	urls []string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.urls = append(t.urls, req.URL.String())
+	return httpmock.NewStringResponse(200, `{"types":["Creature"]}`), nil
+}
+
+func Test_HTTPClient(t *testing.T) {
+	Convey("When configuring a http client", t, func() {
+		transport := new(recordingTransport)
+		Configure(WithHTTPClient(&http.Client{Transport: transport}))
+		defer Configure(WithHTTPClient(nil))
+
+		Convey("all requests should be made with that client", func() {
+			types, err := GetTypes()
+			So(err, ShouldBeNil)
+			So(types, ShouldResemble, []string{"Creature"})
+			So(transport.urls, ShouldResemble, []string{"https://api.magicthegathering.io/v1/types"})
+		})
+	})
+}
+
+// Benchmark_AllConnections fetches a query of 50 pages from a TLS server and reports the number
+// of connections which were opened per query.
+func Benchmark_AllConnections(b *testing.B) {
+	const pages = 50
+	var conns int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page < pages {
+			w.Header().Set("Link", fmt.Sprintf(`<https://%s/v1/cards?page=%d>; rel="next"`, r.Host, page+1))
+		}
+		fmt.Fprintf(w, "{\"cards\":[{\"name\":\"Card %d\",\"id\":\"%d\"}]}\n", page, page)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&conns, 1)
+		}
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	run := func(b *testing.B, client *http.Client) {
+		Configure(WithBaseURL(srv.URL+"/v1/"), WithHTTPClient(client))
+		defer Configure(WithBaseURL(DefaultBaseURL), WithHTTPClient(nil))
+		client.CloseIdleConnections()
+		atomic.StoreInt64(&conns, 0)
+
+		for i := 0; i < b.N; i++ {
+			cards, err := NewQuery().All()
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(cards) != pages {
+				b.Fatalf("expected %d cards, got %d", pages, len(cards))
+			}
+		}
+		b.ReportMetric(float64(atomic.LoadInt64(&conns))/float64(b.N), "conns/op")
+	}
+
+	b.Run("keep-alive", func(b *testing.B) {
+		run(b, srv.Client())
+	})
+	b.Run("no-keep-alive", func(b *testing.B) {
+		client := srv.Client()
+		transport := client.Transport.(*http.Transport).Clone()
+		transport.DisableKeepAlives = true
+		run(b, &http.Client{Transport: transport})
+	})
+}
@@ -0,0 +1,79 @@
+package mtg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Color of a card as used by the API.
+type Color string
+
+const (
+	// ColorWhite is the color white (W).
+	ColorWhite = Color("White")
+	// ColorBlue is the color blue (U).
+	ColorBlue = Color("Blue")
+	// ColorBlack is the color black (B).
+	ColorBlack = Color("Black")
+	// ColorRed is the color red (R).
+	ColorRed = Color("Red")
+	// ColorGreen is the color green (G).
+	ColorGreen = Color("Green")
+	// ColorUnknown is used for all colors which are not known by this package.
+	ColorUnknown = Color("unknown")
+)
+
+var colorCodes = map[Color]string{
+	ColorWhite: "W",
+	ColorBlue:  "U",
+	ColorBlack: "B",
+	ColorRed:   "R",
+	ColorGreen: "G",
+}
+
+func parseColor(s string) Color {
+	for c, code := range colorCodes {
+		if strings.EqualFold(s, string(c)) || strings.EqualFold(s, code) {
+			return c
+		}
+	}
+	return ColorUnknown
+}
+
+// ParseColors returns the colors of the given color names or codes, which are matched case
+// insensitive. An error naming the first unknown color is returned, for example to reject a
+// misspelled color given by a user before passing it to WhereColorIdentityWithin.
+func ParseColors(colors ...string) ([]Color, error) {
+	result := make([]Color, len(colors))
+	for i, s := range colors {
+		if result[i] = parseColor(strings.TrimSpace(s)); result[i] == ColorUnknown {
+			return nil, fmt.Errorf("%q is no valid color", s)
+		}
+	}
+	return result, nil
+}
+
+// Code returns the one letter code of the color as used in the color identity, or an empty
+// string for unknown colors.
+func (c Color) Code() string {
+	return colorCodes[c]
+}
+
+// ColorsValue returns the colors of the card. Colors which are not known are returned as ColorUnknown.
+func (c *Card) ColorsValue() []Color {
+	colors := make([]Color, len(c.Colors))
+	for i, s := range c.Colors {
+		colors[i] = parseColor(s)
+	}
+	return colors
+}
+
+// ColorIdentityValue returns the color identity of the card. Color codes which are not known are
+// returned as ColorUnknown.
+func (c *Card) ColorIdentityValue() []Color {
+	colors := make([]Color, len(c.ColorIdentity))
+	for i, s := range c.ColorIdentity {
+		colors[i] = parseColor(s)
+	}
+	return colors
+}
@@ -0,0 +1,49 @@
+package mtg
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Color(t *testing.T) {
+	Convey("When reading the colors of a card", t, func() {
+		card := &Card{Colors: []string{"Blue", "Red"}, ColorIdentity: []string{"U", "R"}}
+
+		Convey("the color names should be mapped", func() {
+			So(card.ColorsValue(), ShouldResemble, []Color{ColorBlue, ColorRed})
+		})
+		Convey("the color codes of the identity should be mapped", func() {
+			So(card.ColorIdentityValue(), ShouldResemble, []Color{ColorBlue, ColorRed})
+		})
+		Convey("unknown colors should be flagged as ColorUnknown", func() {
+			card := &Card{Colors: []string{"Purple", "Green"}, ColorIdentity: []string{"P"}}
+			So(card.ColorsValue(), ShouldResemble, []Color{ColorUnknown, ColorGreen})
+			So(card.ColorIdentityValue(), ShouldResemble, []Color{ColorUnknown})
+		})
+		Convey("colorless cards should have no colors", func() {
+			So((&Card{}).ColorsValue(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("Color codes", t, func() {
+		So(ColorWhite.Code(), ShouldEqual, "W")
+		So(ColorBlue.Code(), ShouldEqual, "U")
+		So(ColorUnknown.Code(), ShouldEqual, "")
+	})
+}
+
+func Test_ParseColors(t *testing.T) {
+	Convey("When parsing colors", t, func() {
+		Convey("names and codes should be accepted", func() {
+			colors, err := ParseColors("red", " G ", "White")
+			So(err, ShouldBeNil)
+			So(colors, ShouldResemble, []Color{ColorRed, ColorGreen, ColorWhite})
+		})
+		Convey("unknown colors should return an error", func() {
+			_, err := ParseColors("R", "grean")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "grean")
+		})
+	})
+}
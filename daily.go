@@ -0,0 +1,53 @@
+package mtg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+)
+
+// CardOfTheDay returns a card selected by the day of t, for example for a daily feature. The
+// Random endpoint of the API can not be seeded, so the day is hashed to an index into all cards
+// and the card at that position is fetched as a page of a single card. The selection is
+// deterministic: every call for the same day returns the same card as long as the cards of the
+// API do not change, while a new set may change the card of any day. The day is taken in the
+// location of t, so use t.UTC() to show the same card everywhere. Two requests are made. If the
+// API does not send the total card count, an error is returned instead of a card which would be
+// the same every day.
+func CardOfTheDay(t time.Time) (*Card, error) {
+	q := NewQuery()
+	_, header, err := fetchCards(context.Background(), q.PageURL(1, 1), false, nil)
+	if err != nil {
+		return nil, err
+	}
+	totals := header["Total-Count"]
+	if len(totals) == 0 {
+		// without the count every day would get the first card
+		return nil, errors.New("the API returned no Total-Count to select the card of the day")
+	}
+	total, err := strconv.Atoi(totals[0])
+	if err != nil {
+		return nil, err
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("%w: the API returned no cards", ErrCardNotFound)
+	}
+	cards, _, err := q.PageS(dayIndex(t, total)+1, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("%w: no card of the day for %s", ErrCardNotFound, t.Format("2006-01-02"))
+	}
+	return cards[0], nil
+}
+
+// dayIndex returns the index of the card of the day of t among count cards.
+func dayIndex(t time.Time, count int) int {
+	h := fnv.New64a()
+	h.Write([]byte(t.Format("2006-01-02")))
+	return int(h.Sum64() % uint64(count))
+}
@@ -0,0 +1,59 @@
+package mtg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_CardOfTheDay(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching the card of the day", t, func() {
+		day := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+		index := dayIndex(day, 10)
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?page=1&pageSize=1",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Card 0","set":"LEA","id":"a"}]}`,
+				map[string]string{"Total-Count": "10"}))
+		httpmock.RegisterResponder("GET", fmt.Sprintf("https://api.magicthegathering.io/v1/cards?page=%d&pageSize=1", index+1),
+			NewStringResponderWithHeader(200, fmt.Sprintf(`{"cards":[{"name":"Card %d","set":"LEA","id":"b"}]}`, index),
+				map[string]string{"Total-Count": "10"}))
+
+		Convey("the card at the index of the day should be returned", func() {
+			card, err := CardOfTheDay(day)
+			So(err, ShouldBeNil)
+			So(card.Name, ShouldEqual, fmt.Sprintf("Card %d", index))
+		})
+
+		Convey("the index should only depend on the day", func() {
+			So(dayIndex(day.Add(10*time.Hour), 10), ShouldEqual, index)
+			So(dayIndex(day, 1), ShouldEqual, 0)
+
+			indexes := map[int]bool{}
+			for i := 0; i < 30; i++ {
+				indexes[dayIndex(day.AddDate(0, 0, i), 1000)] = true
+			}
+			So(len(indexes), ShouldBeGreaterThan, 1)
+		})
+
+		Convey("a missing total count should return an error", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?page=1&pageSize=1",
+				httpmock.NewStringResponder(200, `{"cards":[{"name":"Card 0","set":"LEA","id":"a"}]}`))
+			card, err := CardOfTheDay(day)
+			So(err, ShouldNotBeNil)
+			So(card, ShouldBeNil)
+		})
+
+		Convey("no cards should return ErrCardNotFound", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?page=1&pageSize=1",
+				NewStringResponderWithHeader(200, `{"cards":[]}`, map[string]string{"Total-Count": "0"}))
+			_, err := CardOfTheDay(day)
+			So(errors.Is(err, ErrCardNotFound), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,62 @@
+package mtg
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DebugRequest describes a request recorded in a DebugInfo.
+type DebugRequest struct {
+	// URL is the requested URL.
+	URL string
+	// Params are the query parameters of the URL.
+	Params url.Values
+	// StatusCode is the status of the response, or zero if no response was received.
+	StatusCode int
+	// Duration is the time until the response headers were received.
+	Duration time.Duration
+	// Err is the error if the request failed before a response was received.
+	Err error
+}
+
+// DebugInfo records the requests made with a context returned by WithDebugInfo, for example to
+// check in a test that a query requested the expected URL. Unlike the debug flag of Query, which
+// prints the requests, the recorded requests can be inspected by the program. A DebugInfo is
+// safe for concurrent use.
+type DebugInfo struct {
+	mu       sync.Mutex
+	requests []DebugRequest
+}
+
+// Requests returns the recorded requests in the order they were made, including each attempt of
+// a retried request.
+func (d *DebugInfo) Requests() []DebugRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]DebugRequest(nil), d.requests...)
+}
+
+func (d *DebugInfo) record(r DebugRequest) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.requests = append(d.requests, r)
+}
+
+type debugInfoKey struct{}
+
+// WithDebugInfo returns a context which records all requests made with it in info. Pass it to
+// the functions taking a context, like Query.AllContext, Query.PageResultContext,
+// Query.RandomContext, SetQuery.AllContext, FetchSetsContext or CardId.FetchContext. Functions
+// without a context, like Query.Page or SetQuery.All, use their own context, so their requests
+// are never recorded; use their context variants instead.
+func WithDebugInfo(ctx context.Context, info *DebugInfo) context.Context {
+	return context.WithValue(ctx, debugInfoKey{}, info)
+}
+
+// debugInfoFrom returns the DebugInfo of ctx, or nil if there is none.
+func debugInfoFrom(ctx context.Context) *DebugInfo {
+	info, _ := ctx.Value(debugInfoKey{}).(*DebugInfo)
+	return info
+}
@@ -0,0 +1,85 @@
+package mtg
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_DebugInfo(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When recording the requests of a call", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&set=LEA",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`))
+		var info DebugInfo
+		ctx := WithDebugInfo(context.Background(), &info)
+
+		Convey("the URL, parameters and status should be recorded", func() {
+			cards, err := NewQuery().Where(CardName, "Bolt").Where(CardSet, "LEA").AllContext(ctx)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+
+			requests := info.Requests()
+			So(requests, ShouldHaveLength, 1)
+			So(requests[0].URL, ShouldEqual, "https://api.magicthegathering.io/v1/cards?name=Bolt&set=LEA")
+			So(requests[0].Params, ShouldResemble, url.Values{"name": {"Bolt"}, "set": {"LEA"}})
+			So(requests[0].StatusCode, ShouldEqual, 200)
+			So(requests[0].Err, ShouldBeNil)
+		})
+
+		Convey("failed requests should be recorded with their error", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/types",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			_, err := GetTypesContext(ctx)
+			So(err, ShouldNotBeNil)
+
+			requests := info.Requests()
+			So(requests, ShouldHaveLength, 1)
+			So(requests[0].StatusCode, ShouldEqual, 0)
+			So(requests[0].Err, ShouldNotBeNil)
+		})
+
+		Convey("pages, random cards and sets should be recorded as well", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2&pageSize=10",
+				httpmock.NewStringResponder(200, `{"cards":[]}`))
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&pageSize=3&random=true",
+				httpmock.NewStringResponder(200, `{"cards":[]}`))
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets?name=Khans",
+				httpmock.NewStringResponder(200, `{"sets":[]}`))
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets?name=Khans&page=1&pageSize=5",
+				httpmock.NewStringResponder(200, `{"sets":[]}`))
+
+			_, err := NewQuery().Where(CardName, "Bolt").PageResultContext(ctx, 2, 10)
+			So(err, ShouldBeNil)
+			_, err = NewQuery().Where(CardName, "Bolt").RandomContext(ctx, 3)
+			So(err, ShouldBeNil)
+			_, err = NewSetQuery().Where(SetName, "Khans").AllContext(ctx)
+			So(err, ShouldBeNil)
+			_, _, err = NewSetQuery().Where(SetName, "Khans").PageSContext(ctx, 1, 5)
+			So(err, ShouldBeNil)
+
+			var urls []string
+			for _, r := range info.Requests() {
+				urls = append(urls, r.URL)
+			}
+			So(urls, ShouldResemble, []string{
+				"https://api.magicthegathering.io/v1/cards?name=Bolt&page=2&pageSize=10",
+				"https://api.magicthegathering.io/v1/cards?name=Bolt&pageSize=3&random=true",
+				"https://api.magicthegathering.io/v1/sets?name=Khans",
+				"https://api.magicthegathering.io/v1/sets?name=Khans&page=1&pageSize=5",
+			})
+		})
+
+		Convey("requests without the context should not be recorded", func() {
+			_, err := NewQuery().Where(CardName, "Bolt").Where(CardSet, "LEA").All()
+			So(err, ShouldBeNil)
+			So(info.Requests(), ShouldBeEmpty)
+		})
+	})
+}
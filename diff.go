@@ -0,0 +1,66 @@
+package mtg
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Equal reports whether both cards have the same Id and the same content in all fields. Cards
+// are compared by their JSON encoding, so nil and empty lists are not equal. Two nil cards are
+// equal.
+func (c *Card) Equal(other *Card) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	if c.Id != other.Id {
+		return false
+	}
+	a, errA := json.Marshal(c)
+	b, errB := json.Marshal(other)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// diffKey returns the identity of a card in DiffCards.
+func diffKey(c *Card) string {
+	if c.Id != "" {
+		return "id:" + string(c.Id)
+	}
+	return "card:" + string(c.Set) + "|" + c.Number + "|" + c.Name
+}
+
+// DiffCards compares two lists of cards, for example a local copy with freshly fetched cards.
+// Cards are identified by their Id; cards without an Id (which the API always sets) are
+// identified by set, collector number and name. The order of the lists does not matter.
+//
+// added contains the cards of newCards which are not in oldCards, removed the cards of oldCards
+// which are not in newCards, both in the order of their list. changed contains the cards of
+// newCards whose identity is in both lists but whose content differs according to Card.Equal,
+// like an updated oracle text. If a list contains a card twice, the first one is used.
+func DiffCards(oldCards, newCards []*Card) (added, removed, changed []*Card) {
+	oldByKey := make(map[string]*Card, len(oldCards))
+	for _, c := range oldCards {
+		if k := diffKey(c); oldByKey[k] == nil {
+			oldByKey[k] = c
+		}
+	}
+	newKeys := make(map[string]bool, len(newCards))
+	for _, c := range newCards {
+		k := diffKey(c)
+		if newKeys[k] {
+			continue
+		}
+		newKeys[k] = true
+		switch prev, ok := oldByKey[k]; {
+		case !ok:
+			added = append(added, c)
+		case !prev.Equal(c):
+			changed = append(changed, c)
+		}
+	}
+	for _, c := range oldCards {
+		if k := diffKey(c); !newKeys[k] && oldByKey[k] == c {
+			removed = append(removed, c)
+		}
+	}
+	return added, removed, changed
+}
@@ -0,0 +1,54 @@
+package mtg
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_CardEqual(t *testing.T) {
+	Convey("When comparing two cards", t, func() {
+		a := &Card{Name: "Lightning Bolt", Set: "LEA", Id: "a", Text: "Lightning Bolt deals 3 damage to any target."}
+		b := &Card{Name: "Lightning Bolt", Set: "LEA", Id: "a", Text: "Lightning Bolt deals 3 damage to any target."}
+
+		So(a.Equal(b), ShouldBeTrue)
+		b.Text = "Lightning Bolt deals 3 damage to target creature or player."
+		So(a.Equal(b), ShouldBeFalse)
+		So(a.Equal(&Card{Name: "Lightning Bolt", Set: "LEA", Id: "b", Text: a.Text}), ShouldBeFalse)
+		So(a.Equal(nil), ShouldBeFalse)
+		So((*Card)(nil).Equal(nil), ShouldBeTrue)
+	})
+}
+
+func Test_DiffCards(t *testing.T) {
+	Convey("When diffing two card lists", t, func() {
+		bolt := &Card{Name: "Lightning Bolt", Set: "LEA", Id: "a"}
+		shock := &Card{Name: "Shock", Set: "M19", Id: "b"}
+		yeti := &Card{Name: "Karplusan Yeti", Set: "ICE", Id: "c"}
+
+		Convey("reordered but identical lists should have no differences", func() {
+			copyOf := func(c *Card) *Card { cc := *c; return &cc }
+			added, removed, changed := DiffCards([]*Card{bolt, shock, yeti}, []*Card{copyOf(yeti), copyOf(bolt), copyOf(shock)})
+			So(added, ShouldBeEmpty)
+			So(removed, ShouldBeEmpty)
+			So(changed, ShouldBeEmpty)
+		})
+
+		Convey("added, removed and changed cards should be found", func() {
+			updated := &Card{Name: "Shock", Set: "M19", Id: "b", Text: "Shock deals 2 damage to any target."}
+			added, removed, changed := DiffCards([]*Card{bolt, shock}, []*Card{updated, yeti})
+			So(added, ShouldResemble, []*Card{yeti})
+			So(removed, ShouldResemble, []*Card{bolt})
+			So(changed, ShouldResemble, []*Card{updated})
+		})
+
+		Convey("cards without Id should be identified by set, number and name", func() {
+			local := &Card{Name: "Forest", Set: "LEA", Number: "294"}
+			other := &Card{Name: "Forest", Set: "LEA", Number: "295"}
+			added, removed, changed := DiffCards([]*Card{local}, []*Card{{Name: "Forest", Set: "LEA", Number: "294"}, other})
+			So(added, ShouldResemble, []*Card{other})
+			So(removed, ShouldBeEmpty)
+			So(changed, ShouldBeEmpty)
+		})
+	})
+}
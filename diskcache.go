@@ -0,0 +1,94 @@
+package mtg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// DiskCacheOptions configures a cache created with NewDiskCache.
+type DiskCacheOptions struct {
+	// Compress stores the responses gzip-compressed. The JSON of the cards compresses very well,
+	// which saves most of the space of big dumps at the cost of decompressing every hit. Entries
+	// stored with a different setting are not found.
+	Compress bool
+}
+
+// NewDiskCache creates a Cache which stores every response in its own file in dir, so the
+// responses survive restarts of the program. The directory is created if it does not exist.
+// Since Cache can not return errors, responses which can not be read or written are treated as
+// missing.
+func NewDiskCache(dir string, opts DiskCacheOptions) (Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &diskCache{dir: dir, compress: opts.Compress}, nil
+}
+
+type diskCache struct {
+	dir      string
+	compress bool
+}
+
+// path returns the file of the key. Keys are URLs, so they are hashed to get valid file names.
+func (d *diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:]) + ".json"
+	if d.compress {
+		name += ".gz"
+	}
+	return filepath.Join(d.dir, name)
+}
+
+func (d *diskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+	if !d.compress {
+		return data, true
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, false
+	}
+	data, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func (d *diskCache) Set(key string, data []byte) {
+	if d.compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return
+		}
+		if err := zw.Close(); err != nil {
+			return
+		}
+		data = buf.Bytes()
+	}
+
+	// write to a temporary file first, so concurrent readers never see a partial entry
+	f, err := os.CreateTemp(d.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), d.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
@@ -0,0 +1,80 @@
+package mtg
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_DiskCache(t *testing.T) {
+	Convey("When caching responses on disk", t, func() {
+		dir := t.TempDir()
+		data := largeCardsFixture(100)
+
+		for _, compress := range []bool{false, true} {
+			cache, err := NewDiskCache(dir, DiskCacheOptions{Compress: compress})
+			So(err, ShouldBeNil)
+
+			_, ok := cache.Get("https://api.magicthegathering.io/v1/cards/1")
+			So(ok, ShouldBeFalse)
+
+			cache.Set("https://api.magicthegathering.io/v1/cards/1", data)
+			cached, ok := cache.Get("https://api.magicthegathering.io/v1/cards/1")
+			So(ok, ShouldBeTrue)
+			So(bytes.Equal(cached, data), ShouldBeTrue)
+		}
+
+		Convey("compressed entries should be smaller", func() {
+			files, err := filepath.Glob(filepath.Join(dir, "*.json*"))
+			So(err, ShouldBeNil)
+			So(files, ShouldHaveLength, 2)
+			sizes := make(map[string]int64)
+			for _, f := range files {
+				info, err := os.Stat(f)
+				So(err, ShouldBeNil)
+				sizes[filepath.Ext(f)] = info.Size()
+			}
+			So(sizes[".gz"], ShouldBeLessThan, sizes[".json"]/10)
+		})
+
+		Convey("the directory should be created", func() {
+			_, err := NewDiskCache(filepath.Join(dir, "a", "b"), DiskCacheOptions{})
+			So(err, ShouldBeNil)
+			info, err := os.Stat(filepath.Join(dir, "a", "b"))
+			So(err, ShouldBeNil)
+			So(info.IsDir(), ShouldBeTrue)
+		})
+	})
+}
+
+func benchmarkDiskCache(b *testing.B, compress bool) {
+	cache, err := NewDiskCache(b.TempDir(), DiskCacheOptions{Compress: compress})
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := largeCardsFixture(5000)
+	cache.Set("cards", data)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cached, ok := cache.Get("cards")
+		if !ok {
+			b.Fatal("cache miss")
+		}
+		if _, err := decodeCards(bytes.NewReader(cached)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_DiskCache(b *testing.B) {
+	benchmarkDiskCache(b, false)
+}
+
+func Benchmark_DiskCacheCompressed(b *testing.B) {
+	benchmarkDiskCache(b, true)
+}
@@ -60,6 +60,17 @@ func ExampleId_fetch() {
 	fetchCardID(CardId("9d91ef4896ab4c1a5611d4d06971fc8026dd2f3f"))
 }
 
+func ExampleCard_Summary() {
+	cards, err := NewQuery().WhereName("Lightning Bolt", MatchExact).All()
+	if err != nil {
+		log.Panic(err)
+	}
+	for _, c := range cards {
+		// e.g. "Lightning Bolt {R} — Instant (LEA)"
+		log.Println(c.Summary())
+	}
+}
+
 func ExampleQuery_random() {
 	// Fetch 2 random red rare cards
 	cards, err := NewQuery().Where(CardRarity, "rare").Where(CardColors, "red").Random(2)
@@ -0,0 +1,157 @@
+package mtg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DownloadOptions configures DownloadAllSets.
+type DownloadOptions struct {
+	// Concurrency is the maximum number of sets which are downloaded at the same time. Values
+	// below 1 download one set after the other.
+	Concurrency int
+	// Progress is called after each set was downloaded or skipped. The calls never overlap, so
+	// the function does not need to be safe for concurrent use.
+	Progress func(DownloadProgress)
+}
+
+// DownloadProgress describes a set handled by DownloadAllSets.
+type DownloadProgress struct {
+	// Set is the code of the set.
+	Set SetCode
+	// Skipped reports whether the file of the set existed already, so it was not downloaded.
+	Skipped bool
+	// Cards is the number of cards written, which is zero for skipped sets.
+	Cards int
+	// Done is the number of sets handled so far, including this one.
+	Done int
+	// Total is the number of sets which are handled.
+	Total int
+}
+
+// DownloadAllSets fetches the cards of every set and writes them to dir, one JSON file per set
+// named by its code (like KTK.json), for example to build a dataset for offline use. The files
+// contain a JSON array of cards, which can be read with LoadCardsFromReader. The download is
+// resumable: sets whose file exists already are skipped, and files are only created once all
+// cards of the set were fetched. If a set fails, the remaining sets are not downloaded and the
+// first error is returned.
+func DownloadAllSets(dir string, opts DownloadOptions) error {
+	return DownloadAllSetsContext(context.Background(), dir, opts)
+}
+
+// DownloadAllSetsContext works like DownloadAllSets. The download is aborted when ctx is done;
+// the sets written so far are kept.
+func DownloadAllSetsContext(ctx context.Context, dir string, opts DownloadOptions) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	sets, err := NewSetQuery().AllContext(ctx)
+	if err != nil {
+		return err
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	parent := ctx
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int
+	)
+	report := func(p DownloadProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		p.Done, p.Total = done, len(sets)
+		if opts.Progress != nil {
+			opts.Progress(p)
+		}
+	}
+	jobs := make(chan SetCode)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for code := range jobs {
+				p, err := downloadSet(ctx, dir, code)
+				if err != nil {
+					once.Do(func() {
+						firstErr = fmt.Errorf("set %s: %w", code, err)
+						cancel()
+					})
+					continue
+				}
+				report(p)
+			}
+		}()
+	}
+	for _, set := range sets {
+		select {
+		case jobs <- set.SetCode:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := parent.Err(); err != nil {
+		// the requests failed because the caller canceled them
+		return err
+	}
+	return firstErr
+}
+
+// downloadSet writes the cards of the set to its file in dir unless the file exists.
+func downloadSet(ctx context.Context, dir string, code SetCode) (DownloadProgress, error) {
+	p := DownloadProgress{Set: code}
+	path := filepath.Join(dir, string(code)+".json")
+	if _, err := os.Stat(path); err == nil {
+		p.Skipped = true
+		return p, nil
+	}
+	cards, err := NewQuery().Where(CardSet, string(code)).AllContext(ctx)
+	if err != nil {
+		return p, err
+	}
+	if cards == nil {
+		cards = []*Card{}
+	}
+	data, err := json.Marshal(cards)
+	if err != nil {
+		return p, err
+	}
+
+	// write to a temporary file first, so an interrupted download is not skipped when resuming
+	f, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		return p, err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return p, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return p, err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return p, err
+	}
+	p.Cards = len(cards)
+	return p, nil
+}
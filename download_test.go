@@ -0,0 +1,75 @@
+package mtg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_DownloadAllSets(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When downloading all sets", t, func() {
+		dir := t.TempDir()
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets",
+			httpmock.NewStringResponder(200, `{"sets":[{"code":"LEA","name":"Limited Edition Alpha"},{"code":"KTK","name":"Khans of Tarkir"}]}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=LEA",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"},{"name":"Black Lotus","set":"LEA","id":"b"}]}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=KTK",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Abzan Charm","set":"KTK","id":"c"}]}`))
+
+		Convey("every set should be written to its own file", func() {
+			var progress []DownloadProgress
+			err := DownloadAllSets(dir, DownloadOptions{Concurrency: 2, Progress: func(p DownloadProgress) {
+				progress = append(progress, p)
+			}})
+			So(err, ShouldBeNil)
+
+			f, err := os.Open(filepath.Join(dir, "LEA.json"))
+			So(err, ShouldBeNil)
+			defer f.Close()
+			cards, err := LoadCardsFromReader(f)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(cards, ShouldContainCard, "Black Lotus")
+
+			So(progress, ShouldHaveLength, 2)
+			So(progress[1].Done, ShouldEqual, 2)
+			So(progress[1].Total, ShouldEqual, 2)
+			So(progress[0].Cards+progress[1].Cards, ShouldEqual, 3)
+		})
+
+		Convey("existing files should be skipped", func() {
+			So(os.WriteFile(filepath.Join(dir, "LEA.json"), []byte(`[]`), 0o644), ShouldBeNil)
+			httpmock.ZeroCallCounters()
+
+			var skipped []SetCode
+			err := DownloadAllSets(dir, DownloadOptions{Progress: func(p DownloadProgress) {
+				if p.Skipped {
+					skipped = append(skipped, p.Set)
+				}
+			}})
+			So(err, ShouldBeNil)
+			So(skipped, ShouldResemble, []SetCode{"LEA"})
+			So(httpmock.GetCallCountInfo()["GET https://api.magicthegathering.io/v1/cards?set=LEA"], ShouldEqual, 0)
+
+			data, err := os.ReadFile(filepath.Join(dir, "LEA.json"))
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, `[]`)
+		})
+
+		Convey("a failing set should return an error without leaving its file", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=KTK",
+				httpmock.NewStringResponder(500, `{"status":"500","error":"Internal Server Error"}`))
+
+			err := DownloadAllSets(dir, DownloadOptions{})
+			So(err, ShouldNotBeNil)
+			_, err = os.Stat(filepath.Join(dir, "KTK.json"))
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,158 @@
+package mtg
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Some filters can not be expressed with the parameters of the API. They are stored in the query
+// like all other parameters, but with the clientFilterPrefix. Those entries are not sent to the
+// API, instead the fetched cards are filtered with the matching function of clientFilters.
+const clientFilterPrefix = "client:"
+
+var clientFilters = map[string]func(card *Card, value string) bool{
+	"colorIdentityWithin": colorIdentityWithin,
+	"excludeTokens":       notToken,
+	"colorless":           colorlessNonland,
+	"multicolor":          multicolored,
+	"hasText":             hasText,
+	"artistExact":         artistExact,
+	"nameExact":           nameExact,
+	"setNameExact":        setNameExact,
+}
+
+// values returns the parameters of the query which are sent to the API.
+func (q query) values() url.Values {
+	queryVals := make(url.Values)
+	for k, v := range q {
+		if !strings.HasPrefix(k, clientFilterPrefix) {
+			queryVals.Set(k, v)
+		}
+	}
+	return queryVals
+}
+
+// filter returns the cards matching all client side filters of the query.
+func (q query) filter(cards []*Card) []*Card {
+	var filters []func(*Card) bool
+	for k, v := range q {
+		if !strings.HasPrefix(k, clientFilterPrefix) {
+			continue
+		}
+		if f, ok := clientFilters[strings.TrimPrefix(k, clientFilterPrefix)]; ok {
+			value := v
+			filters = append(filters, func(c *Card) bool { return f(c, value) })
+		}
+	}
+	if len(filters) == 0 {
+		return cards
+	}
+
+	result := cards[:0:0]
+	for _, c := range cards {
+		matches := true
+		for _, f := range filters {
+			if !f(c) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// unknownColorCode marks an unknown color given to WhereColorIdentityWithin, which makes the
+// filter match no card.
+const unknownColorCode = "?"
+
+func colorIdentityWithin(card *Card, codes string) bool {
+	if strings.Contains(codes, unknownColorCode) {
+		return false
+	}
+	for _, c := range card.ColorIdentityValue() {
+		if c == ColorUnknown || !strings.Contains(codes, c.Code()) {
+			return false
+		}
+	}
+	return true
+}
+
+// exactSep separates the values of an exact filter given several times, which must all match
+// like the values Where joins for the API. Names never contain a line break.
+const exactSep = "\n"
+
+// whereExact adds value to the exact client filter with the given name.
+func (q query) whereExact(filter, value string) {
+	key := clientFilterPrefix + filter
+	if prev, ok := q[key]; ok {
+		value = prev + exactSep + value
+	}
+	q[key] = value
+}
+
+// matchesAll reports whether match is true for every value of an exact filter.
+func matchesAll(values string, match func(value string) bool) bool {
+	for _, v := range strings.Split(values, exactSep) {
+		if !match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func artistExact(card *Card, artists string) bool {
+	return matchesAll(artists, func(artist string) bool {
+		return strings.EqualFold(strings.TrimSpace(card.Artist), strings.TrimSpace(artist))
+	})
+}
+
+func nameExact(card *Card, names string) bool {
+	return matchesAll(names, func(name string) bool {
+		return MatchName(card.Name, name)
+	})
+}
+
+func setNameExact(card *Card, names string) bool {
+	return matchesAll(names, func(name string) bool {
+		return strings.EqualFold(strings.TrimSpace(card.SetName), strings.TrimSpace(name))
+	})
+}
+
+func notToken(card *Card, _ string) bool {
+	return card.LayoutType() != LayoutToken
+}
+
+func colorlessNonland(card *Card, _ string) bool {
+	if len(card.Colors) > 0 {
+		return false
+	}
+	for _, t := range card.TypeLine().Types {
+		if t == "Land" {
+			return false
+		}
+	}
+	return true
+}
+
+func multicolored(card *Card, _ string) bool {
+	return len(card.Colors) >= 2
+}
+
+func hasText(card *Card, _ string) bool {
+	return strings.TrimSpace(card.Text) != ""
+}
+
+// HasText returns the cards which have rules text, leaving out vanilla creatures and basic
+// lands. It is the filter of Query.WhereHasText for cards which were already fetched.
+func HasText(cards []*Card) []*Card {
+	var result []*Card
+	for _, c := range cards {
+		if hasText(c, "") {
+			result = append(result, c)
+		}
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package mtg
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_ColorIdentityWithin(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When filtering by a commander's color identity", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?type=Artifact",
+			httpmock.NewStringResponder(200, `{"cards":[
+				{"name":"Sol Ring","colorIdentity":[],"id":"a"},
+				{"name":"Sword of Fire and Ice","colorIdentity":[],"id":"b"},
+				{"name":"Jor Kadeen, the Prevailer","colorIdentity":["R","W"],"id":"c"},
+				{"name":"Scuttling Doom Engine","id":"d"},
+				{"name":"Thopter Foundry","colorIdentity":["W","B","U"],"id":"e"},
+				{"name":"Ichor Wellspring","colorIdentity":["R"],"id":"f"}
+			]}`))
+		qry := NewQuery().Where(CardType, "Artifact")
+
+		Convey("the filter should not be sent to the API", func() {
+			So(qry.WhereColorIdentityWithin("W", "U").(query).values().Encode(), ShouldEqual, "type=Artifact")
+		})
+
+		Convey("a colorless commander should exclude all cards with colored pips", func() {
+			cards, err := qry.WhereColorIdentityWithin().All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 3)
+			So(cards, ShouldContainCard, "Sol Ring")
+			So(cards, ShouldContainCard, "Sword of Fire and Ice")
+			So(cards, ShouldContainCard, "Scuttling Doom Engine")
+		})
+
+		Convey("cards with a subset of the colors should be kept", func() {
+			cards, err := qry.WhereColorIdentityWithin("Red", "White").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 5)
+			So(cards, ShouldContainCard, "Jor Kadeen, the Prevailer")
+			So(cards, ShouldContainCard, "Ichor Wellspring")
+		})
+
+		Convey("color codes and names should both be accepted", func() {
+			cards, err := qry.WhereColorIdentityWithin("r").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 4)
+		})
+
+		Convey("unknown colors mixed with known ones should match no cards", func() {
+			cards, err := qry.WhereColorIdentityWithin("r", "grean").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldBeEmpty)
+		})
+
+		Convey("only unknown colors should match no cards instead of all or colorless ones", func() {
+			cards, err := qry.WhereColorIdentityWithin("grean").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldBeEmpty)
+		})
+
+		Convey("the filter should be applied to pages as well", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?page=1&pageSize=100&type=Artifact",
+				httpmock.NewStringResponder(200, `{"cards":[{"name":"Sol Ring","id":"a"},{"name":"Ichor Wellspring","colorIdentity":["R"],"id":"f"}]}`))
+			cards, _, err := qry.WhereColorIdentityWithin().Page(1)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+			So(cards, ShouldContainCard, "Sol Ring")
+		})
+	})
+}
+
+func Test_ExcludeTokens(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When excluding tokens", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Goblin",
+			httpmock.NewStringResponder(200, `{"cards":[
+				{"name":"Goblin Guide","layout":"normal","id":"a"},
+				{"name":"Goblin","layout":"token","id":"b"},
+				{"name":"Goblin Bombardment","id":"c"}
+			]}`))
+		qry := NewQuery().Where(CardName, "Goblin")
+
+		Convey("the filter should not be sent to the API", func() {
+			So(qry.WhereExcludeTokens().URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?name=Goblin")
+		})
+
+		Convey("tokens should be removed from the results", func() {
+			cards, err := qry.WhereExcludeTokens().All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(cards, ShouldContainCard, "Goblin Guide")
+			So(cards, ShouldContainCard, "Goblin Bombardment")
+		})
+	})
+}
+
+func Test_Colorless(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When filtering colorless cards", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=BFZ",
+			httpmock.NewStringResponder(200, `{"cards":[
+				{"name":"Ulamog, the Ceaseless Hunger","type":"Legendary Creature — Eldrazi","types":["Creature"],"id":"a"},
+				{"name":"Drowner of Hope","colors":["Blue"],"types":["Creature"],"id":"b"},
+				{"name":"Prairie Stream","type":"Land — Plains Island","types":["Land"],"id":"c"},
+				{"name":"Hedron Archive","type":"Artifact","id":"d"}
+			]}`))
+		qry := NewQuery().Where(CardSet, "BFZ")
+
+		Convey("the filter should not be sent to the API", func() {
+			So(qry.WhereColorless().URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?set=BFZ")
+		})
+
+		Convey("only colorless nonland cards should be kept", func() {
+			cards, err := qry.WhereColorless().All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(cards, ShouldContainCard, "Ulamog, the Ceaseless Hunger")
+			So(cards, ShouldContainCard, "Hedron Archive")
+		})
+	})
+}
+
+func Test_Multicolor(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When filtering multicolor cards", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=RTR",
+			httpmock.NewStringResponder(200, `{"cards":[
+				{"name":"Niv-Mizzet, Dracogenius","manaCost":"{2}{U}{U}{R}{R}","colors":["Blue","Red"],"id":"a"},
+				{"name":"Boros Guildmage","manaCost":"{R/W}{R/W}","colors":["Red","White"],"id":"b"},
+				{"name":"Lightning Bolt","manaCost":"{R}","colors":["Red"],"id":"c"},
+				{"name":"Azorius Keyrune","manaCost":"{3}","id":"d"}
+			]}`))
+		qry := NewQuery().Where(CardSet, "RTR")
+
+		Convey("the filter should not be sent to the API", func() {
+			So(qry.WhereMulticolor().URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?set=RTR")
+		})
+
+		Convey("only cards with two or more colors should be kept", func() {
+			cards, err := qry.WhereMulticolor().All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(cards, ShouldContainCard, "Niv-Mizzet, Dracogenius")
+			So(cards, ShouldContainCard, "Boros Guildmage")
+		})
+	})
+}
+
+func Test_HasText(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When filtering cards with rules text", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=M10",
+			httpmock.NewStringResponder(200, `{"cards":[
+				{"name":"Grizzly Bears","type":"Creature — Bear","id":"a"},
+				{"name":"Forest","type":"Basic Land — Forest","text":" ","id":"b"},
+				{"name":"Serra Angel","text":"Flying, vigilance","id":"c"},
+				{"name":"Lightning Bolt","text":"Lightning Bolt deals 3 damage to any target.","id":"d"}
+			]}`))
+		qry := NewQuery().Where(CardSet, "M10")
+
+		Convey("the filter should not be sent to the API", func() {
+			So(qry.WhereHasText().URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?set=M10")
+		})
+
+		Convey("only cards with text should be kept", func() {
+			cards, err := qry.WhereHasText().All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(cards, ShouldContainCard, "Serra Angel")
+			So(cards, ShouldContainCard, "Lightning Bolt")
+		})
+
+		Convey("fetched cards should be filtered the same way", func() {
+			cards := HasText([]*Card{{Name: "Grizzly Bears"}, {Name: "Serra Angel", Text: "Flying, vigilance"}})
+			So(cards, ShouldHaveLength, 1)
+			So(cards[0].Name, ShouldEqual, "Serra Angel")
+		})
+	})
+}
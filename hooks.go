@@ -0,0 +1,102 @@
+package mtg
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RequestInfo describes a single request made to the API. It is passed to the hook set with
+// WithRequestHook once the request is finished.
+type RequestInfo struct {
+	// URL is the requested URL.
+	URL string
+	// StatusCode is the status of the response, or zero if no response was received.
+	StatusCode int
+	// Duration is the time until the response headers were received.
+	Duration time.Duration
+	// ReadDuration is the time from receiving the headers until the body was closed, which
+	// includes decoding the cards or sets.
+	ReadDuration time.Duration
+	// Bytes is the number of bytes of the response body which were read.
+	Bytes int64
+	// Err is the error if the request failed before a response was received.
+	Err error
+}
+
+// WithRequestHook sets a function which is called after every request to the API, including
+// each attempt of a retried request, for example to collect metrics. The hook is called from
+// the goroutine which made the request, so it must be safe for concurrent use if requests are
+// made concurrently. A nil hook disables it, which is the default.
+func WithRequestHook(hook func(RequestInfo)) Option {
+	return func(c *config) {
+		c.requestHook = hook
+	}
+}
+
+// WithResponseHook sets a function which is called with every response of the API before its
+// body is read, including the responses of failed attempts which are retried. It can be used to
+// inspect headers or the status which are not exposed otherwise, like deprecation notices. The
+// body is managed by this package: the hook must neither read nor close it. A nil hook disables
+// it, which is the default.
+func WithResponseHook(hook func(*http.Response)) Option {
+	return func(c *config) {
+		c.responseHook = hook
+	}
+}
+
+// WithCardDecodeHook sets a function which is called with every decoded card together with its
+// JSON, for example to decode fields of the API which Card does not model (yet) into a struct of
+// your own. The card is decoded as usual before; if the hook returns an error, decoding the
+// response fails with that error. The hook applies to all cards decoded by this package,
+// including cached responses and LoadCardsFromReader, and must be safe for concurrent use if
+// requests are made concurrently. A nil hook disables it, which is the default.
+func WithCardDecodeHook(hook func(raw json.RawMessage, card *Card) error) Option {
+	return func(c *config) {
+		c.cardDecodeHook = hook
+	}
+}
+
+// hookBody reports the request to the hook when the body is closed.
+type hookBody struct {
+	io.ReadCloser
+	info RequestInfo
+	hook func(RequestInfo)
+	read time.Time
+	once sync.Once
+}
+
+func (b *hookBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.info.Bytes += int64(n)
+	return n, err
+}
+
+func (b *hookBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.info.ReadDuration = time.Since(b.read)
+		b.hook(b.info)
+	})
+	return err
+}
+
+// doHooked makes the request with client and reports it to the request hook of c.
+func doHooked(c config, client *http.Client, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := client.Do(req)
+	info := RequestInfo{
+		URL:      req.URL.String(),
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if err != nil {
+		c.requestHook(info)
+		return nil, err
+	}
+	info.StatusCode = resp.StatusCode
+	resp.Body = &hookBody{ReadCloser: resp.Body, info: info, hook: c.requestHook, read: time.Now()}
+	return resp, nil
+}
@@ -0,0 +1,137 @@
+package mtg
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_RequestHook(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When a request hook is configured", t, func() {
+		var infos []RequestInfo
+		Configure(WithRequestHook(func(info RequestInfo) {
+			infos = append(infos, info)
+		}))
+		defer Configure(WithRequestHook(nil))
+
+		Convey("successful requests should be reported after reading the body", func() {
+			body := `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+				httpmock.NewStringResponder(200, body))
+
+			cards, err := NewQuery().Where(CardName, "Bolt").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+			So(infos, ShouldHaveLength, 1)
+			So(infos[0].URL, ShouldEqual, "https://api.magicthegathering.io/v1/cards?name=Bolt")
+			So(infos[0].StatusCode, ShouldEqual, 200)
+			So(infos[0].Bytes, ShouldEqual, len(body))
+			So(infos[0].Err, ShouldBeNil)
+		})
+
+		Convey("failed requests should be reported with their error", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+
+			_, err := NewQuery().Where(CardName, "Bolt").All()
+			So(err, ShouldNotBeNil)
+			So(infos, ShouldHaveLength, 1)
+			So(infos[0].StatusCode, ShouldEqual, 0)
+			So(infos[0].Err, ShouldNotBeNil)
+		})
+
+		Convey("server errors should be reported with their status", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+				httpmock.NewStringResponder(500, `{"status":"500","error":"Internal Server Error"}`))
+
+			_, err := NewQuery().Where(CardName, "Bolt").All()
+			So(err, ShouldNotBeNil)
+			So(infos, ShouldHaveLength, 1)
+			So(infos[0].StatusCode, ShouldEqual, 500)
+		})
+	})
+}
+
+func Test_ResponseHook(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When a response hook is configured", t, func() {
+		var responses []*http.Response
+		Configure(WithResponseHook(func(resp *http.Response) {
+			responses = append(responses, resp)
+		}))
+		defer Configure(WithResponseHook(nil))
+
+		Convey("the headers of every response should be available", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+				NewStringResponderWithHeader(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`,
+					map[string]string{"Deprecation": "true"}))
+
+			cards, err := NewQuery().Where(CardName, "Bolt").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+			So(responses, ShouldHaveLength, 1)
+			So(responses[0].StatusCode, ShouldEqual, 200)
+			So(responses[0].Header.Get("Deprecation"), ShouldEqual, "true")
+		})
+
+		Convey("failed requests should not call the hook", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+
+			_, err := NewQuery().Where(CardName, "Bolt").All()
+			So(err, ShouldNotBeNil)
+			So(responses, ShouldBeEmpty)
+		})
+	})
+}
+
+func Test_CardDecodeHook(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When a card decode hook is configured", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a","experimental":{"score":7}}]}`))
+
+		type extra struct {
+			Experimental struct {
+				Score int `json:"score"`
+			} `json:"experimental"`
+		}
+		extras := map[CardId]extra{}
+		Configure(WithCardDecodeHook(func(raw json.RawMessage, card *Card) error {
+			var e extra
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return err
+			}
+			extras[card.Id] = e
+			return nil
+		}))
+		defer Configure(WithCardDecodeHook(nil))
+
+		Convey("the hook should get the JSON of every card", func() {
+			cards, err := NewQuery().Where(CardName, "Bolt").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+			So(cards[0].Name, ShouldEqual, "Lightning Bolt")
+			So(extras["a"].Experimental.Score, ShouldEqual, 7)
+		})
+
+		Convey("errors of the hook should fail the decoding", func() {
+			Configure(WithCardDecodeHook(func(json.RawMessage, *Card) error {
+				return errors.New("unexpected field")
+			}))
+			_, err := NewQuery().Where(CardName, "Bolt").All()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
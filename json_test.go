@@ -60,6 +60,27 @@ func Test_Date(t *testing.T) {
 	})
 }
 
+func Test_DateMarshal(t *testing.T) {
+	Convey("json date encoding", t, func() {
+		Convey("a date should be encoded as YYYY-MM-DD", func() {
+			data, err := json.Marshal(Date(time.Date(2010, 3, 12, 0, 0, 0, 0, time.UTC)))
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, `"2010-03-12"`)
+		})
+
+		Convey("an encoded date should be decoded to the same date", func() {
+			for _, d := range []Date{Date{}, Date(time.Date(2001, 12, 1, 0, 0, 0, 0, time.UTC))} {
+				data, err := json.Marshal(d)
+				So(err, ShouldBeNil)
+
+				var decoded Date
+				So(json.Unmarshal(data, &decoded), ShouldBeNil)
+				So(decoded, ShouldBeOn, time.Time(d))
+			}
+		})
+	})
+}
+
 func Test_BoosterContent(t *testing.T) {
 	Convey("json BoosterContent decoding", t, func() {
 		var bc BoosterContent
@@ -0,0 +1,91 @@
+package mtg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// KeywordAbilities are the keyword abilities found by Card.Keywords. The list can be changed to
+// find further keywords, like the ones of sets released after this list was written.
+var KeywordAbilities = []string{
+	"Deathtouch", "Defender", "Double strike", "Enchant", "Equip", "First strike", "Flash",
+	"Flying", "Haste", "Hexproof", "Indestructible", "Intimidate", "Landwalk", "Forestwalk",
+	"Islandwalk", "Mountainwalk", "Plainswalk", "Swampwalk", "Lifelink", "Protection", "Reach",
+	"Shroud", "Trample", "Vigilance", "Ward", "Banding", "Rampage", "Cumulative upkeep",
+	"Flanking", "Phasing", "Buyback", "Shadow", "Cycling", "Echo", "Horsemanship", "Fading",
+	"Kicker", "Multikicker", "Flashback", "Madness", "Fear", "Morph", "Megamorph", "Amplify",
+	"Provoke", "Storm", "Affinity", "Entwine", "Modular", "Sunburst", "Bushido", "Soulshift",
+	"Splice", "Ninjutsu", "Epic", "Convoke", "Dredge", "Transmute", "Bloodthirst", "Haunt",
+	"Replicate", "Forecast", "Graft", "Recover", "Ripple", "Split second", "Suspend",
+	"Vanishing", "Absorb", "Aura swap", "Delve", "Fortify", "Frenzy", "Gravestorm", "Poisonous",
+	"Transfigure", "Champion", "Changeling", "Evoke", "Hideaway", "Prowl", "Reinforce",
+	"Conspire", "Persist", "Wither", "Retrace", "Devour", "Exalted", "Unearth", "Cascade",
+	"Annihilator", "Level up", "Rebound", "Totem armor", "Infect", "Battle cry",
+	"Living weapon", "Undying", "Miracle", "Soulbond", "Overload", "Scavenge", "Unleash",
+	"Cipher", "Evolve", "Extort", "Fuse", "Bestow", "Tribute", "Dethrone", "Outlast", "Prowess",
+	"Dash", "Exploit", "Menace", "Renown", "Awaken", "Devoid", "Ingest", "Myriad", "Surge",
+	"Skulk", "Emerge", "Escalate", "Melee", "Crew", "Fabricate", "Partner", "Undaunted",
+	"Improvise", "Aftermath", "Embalm", "Eternalize", "Afflict", "Ascend", "Assist",
+	"Jump-start", "Mentor", "Afterlife", "Riot", "Spectacle", "Escape", "Companion", "Mutate",
+}
+
+// reminderText matches the reminder text in parentheses after keywords.
+var reminderText = regexp.MustCompile(`\([^)]*\)`)
+
+// Keywords returns the keyword abilities (see KeywordAbilities) of the card, found in its text.
+// Only lines which consist of keywords are considered, like "Flying, trample" or "Kicker {2}{G}"
+// but not "Creatures you control have flying.", and reminder text is ignored. The keywords are
+// returned as written in KeywordAbilities in the order they appear in the text.
+//
+// The detection is a heuristic: keywords given to other objects on a line of their own or
+// unusual templating may be missed or found wrongly.
+func (c *Card) Keywords() []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(reminderText.ReplaceAllString(c.Text, ""), "\n") {
+		var found []string
+		for _, part := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ';' }) {
+			kw := keywordOf(strings.TrimSpace(part))
+			if kw == "" {
+				found = nil
+				break
+			}
+			found = append(found, kw)
+		}
+		for _, kw := range found {
+			if !seen[kw] {
+				seen[kw] = true
+				keywords = append(keywords, kw)
+			}
+		}
+	}
+	return keywords
+}
+
+// HasKeyword reports whether the card has the keyword ability, which is matched case
+// insensitive. See Keywords for how keywords are found.
+func (c *Card) HasKeyword(keyword string) bool {
+	for _, kw := range c.Keywords() {
+		if strings.EqualFold(kw, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// keywordOf returns the keyword the part of a keyword line starts with, like "Protection" for
+// "protection from red", or "" if it does not start with a keyword.
+func keywordOf(part string) string {
+	lower := strings.ToLower(part)
+	for _, kw := range KeywordAbilities {
+		l := strings.ToLower(kw)
+		if !strings.HasPrefix(lower, l) {
+			continue
+		}
+		rest := lower[len(l):]
+		if rest == "" || strings.HasPrefix(rest, " ") || strings.HasPrefix(rest, "—") || strings.HasPrefix(rest, "{") {
+			return kw
+		}
+	}
+	return ""
+}
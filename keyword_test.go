@@ -0,0 +1,43 @@
+package mtg
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Keywords(t *testing.T) {
+	Convey("When reading the keywords of a card", t, func() {
+		Convey("keywords on several lines should be found", func() {
+			card := &Card{Text: "Flying, first strike\nLifelink (Damage dealt by this creature also causes you to gain that much life.)\nProtection from red"}
+			So(card.Keywords(), ShouldResemble, []string{"Flying", "First strike", "Lifelink", "Protection"})
+		})
+
+		Convey("keywords with costs should be found", func() {
+			card := &Card{Text: "Kicker {2}{G} (You may pay an additional {2}{G} as you cast this spell.)\nFlashback—Sacrifice a creature."}
+			So(card.Keywords(), ShouldResemble, []string{"Kicker", "Flashback"})
+		})
+
+		Convey("keywords in other abilities should be ignored", func() {
+			card := &Card{Text: "Creatures you control have flying.\nFlash"}
+			So(card.Keywords(), ShouldResemble, []string{"Flash"})
+		})
+
+		Convey("cards without text should have no keywords", func() {
+			So((&Card{}).Keywords(), ShouldBeEmpty)
+		})
+
+		Convey("keywords should be checked case insensitive", func() {
+			card := &Card{Text: "Trample, haste"}
+			So(card.HasKeyword("trample"), ShouldBeTrue)
+			So(card.HasKeyword("Haste"), ShouldBeTrue)
+			So(card.HasKeyword("Flying"), ShouldBeFalse)
+		})
+
+		Convey("the list of keywords should be configurable", func() {
+			defer func(kws []string) { KeywordAbilities = kws }(KeywordAbilities)
+			KeywordAbilities = append(KeywordAbilities, "Toxic")
+			So((&Card{Text: "Toxic 2"}).Keywords(), ShouldResemble, []string{"Toxic"})
+		})
+	})
+}
@@ -0,0 +1,83 @@
+package mtg
+
+// Layout is the layout of a card, such as normal, split or double-faced.
+type Layout string
+
+const (
+	// LayoutNormal is the layout of regular cards.
+	LayoutNormal = Layout("normal")
+	// LayoutSplit is the layout of split cards like Fire // Ice.
+	LayoutSplit = Layout("split")
+	// LayoutAftermath is the layout of split cards with an aftermath half.
+	LayoutAftermath = Layout("aftermath")
+	// LayoutFlip is the layout of flip cards like the Kamigawa flip creatures.
+	LayoutFlip = Layout("flip")
+	// LayoutDoubleFaced is the layout of double-faced cards.
+	LayoutDoubleFaced = Layout("double-faced")
+	// LayoutTransform is the layout of double-faced cards which transform.
+	LayoutTransform = Layout("transform")
+	// LayoutModalDoubleFaced is the layout of modal double-faced cards.
+	LayoutModalDoubleFaced = Layout("modal_dfc")
+	// LayoutMeld is the layout of cards which meld into one card.
+	LayoutMeld = Layout("meld")
+	// LayoutAdventure is the layout of cards with an adventure.
+	LayoutAdventure = Layout("adventure")
+	// LayoutSaga is the layout of saga cards.
+	LayoutSaga = Layout("saga")
+	// LayoutLeveler is the layout of level up cards.
+	LayoutLeveler = Layout("leveler")
+	// LayoutToken is the layout of tokens.
+	LayoutToken = Layout("token")
+	// LayoutPlane is the layout of Planechase planes.
+	LayoutPlane = Layout("plane")
+	// LayoutPhenomenon is the layout of Planechase phenomena.
+	LayoutPhenomenon = Layout("phenomenon")
+	// LayoutScheme is the layout of Archenemy schemes.
+	LayoutScheme = Layout("scheme")
+	// LayoutVanguard is the layout of Vanguard cards.
+	LayoutVanguard = Layout("vanguard")
+	// LayoutUnknown is used for all layouts which are not known by this package.
+	LayoutUnknown = Layout("unknown")
+)
+
+var knownLayouts = map[Layout]bool{
+	LayoutNormal:           true,
+	LayoutSplit:            true,
+	LayoutAftermath:        true,
+	LayoutFlip:             true,
+	LayoutDoubleFaced:      true,
+	LayoutTransform:        true,
+	LayoutModalDoubleFaced: true,
+	LayoutMeld:             true,
+	LayoutAdventure:        true,
+	LayoutSaga:             true,
+	LayoutLeveler:          true,
+	LayoutToken:            true,
+	LayoutPlane:            true,
+	LayoutPhenomenon:       true,
+	LayoutScheme:           true,
+	LayoutVanguard:         true,
+}
+
+// LayoutType returns the layout of the card. Layouts which are not known are returned as LayoutUnknown.
+func (c *Card) LayoutType() Layout {
+	l := Layout(c.Layout)
+	if knownLayouts[l] {
+		return l
+	}
+	return LayoutUnknown
+}
+
+// IsDoubleFaced returns true if cards with this layout have a front and a back face.
+func (l Layout) IsDoubleFaced() bool {
+	switch l {
+	case LayoutDoubleFaced, LayoutTransform, LayoutModalDoubleFaced, LayoutMeld:
+		return true
+	}
+	return false
+}
+
+// IsSplit returns true if cards with this layout have multiple halves on the same face.
+func (l Layout) IsSplit() bool {
+	return l == LayoutSplit || l == LayoutAftermath
+}
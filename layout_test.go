@@ -0,0 +1,39 @@
+package mtg
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Layout(t *testing.T) {
+	Convey("When reading the layout of a card", t, func() {
+		Convey("known layouts should be returned as they are", func() {
+			So((&Card{Layout: "normal"}).LayoutType(), ShouldEqual, LayoutNormal)
+			So((&Card{Layout: "double-faced"}).LayoutType(), ShouldEqual, LayoutDoubleFaced)
+			So((&Card{Layout: "split"}).LayoutType(), ShouldEqual, LayoutSplit)
+		})
+
+		Convey("unknown layouts should be mapped to LayoutUnknown", func() {
+			So((&Card{Layout: "something-new"}).LayoutType(), ShouldEqual, LayoutUnknown)
+			So((&Card{}).LayoutType(), ShouldEqual, LayoutUnknown)
+		})
+	})
+
+	Convey("Layout predicates", t, func() {
+		Convey("double-faced layouts", func() {
+			So(LayoutDoubleFaced.IsDoubleFaced(), ShouldBeTrue)
+			So(LayoutTransform.IsDoubleFaced(), ShouldBeTrue)
+			So(LayoutModalDoubleFaced.IsDoubleFaced(), ShouldBeTrue)
+			So(LayoutNormal.IsDoubleFaced(), ShouldBeFalse)
+			So(LayoutSplit.IsDoubleFaced(), ShouldBeFalse)
+			So(LayoutUnknown.IsDoubleFaced(), ShouldBeFalse)
+		})
+
+		Convey("split layouts", func() {
+			So(LayoutSplit.IsSplit(), ShouldBeTrue)
+			So(LayoutAftermath.IsSplit(), ShouldBeTrue)
+			So(LayoutFlip.IsSplit(), ShouldBeFalse)
+		})
+	})
+}
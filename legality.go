@@ -0,0 +1,61 @@
+package mtg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LegalityStatus is the legality of a card in a game format as used by the API.
+type LegalityStatus string
+
+const (
+	// LegalityLegal is used for cards which may be played in a format.
+	LegalityLegal = LegalityStatus("Legal")
+	// LegalityBanned is used for cards which may not be played in a format.
+	LegalityBanned = LegalityStatus("Banned")
+	// LegalityRestricted is used for cards of which only a single copy may be played in a format.
+	LegalityRestricted = LegalityStatus("Restricted")
+)
+
+var legalityNames = map[string]LegalityStatus{
+	"legal":      LegalityLegal,
+	"banned":     LegalityBanned,
+	"restricted": LegalityRestricted,
+}
+
+// ParseLegality returns the LegalityStatus of s, which is matched case insensitive. An error is
+// returned if s is no legality known by the API.
+func ParseLegality(s string) (LegalityStatus, error) {
+	if l, ok := legalityNames[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return l, nil
+	}
+	return "", fmt.Errorf("%q is no valid legality", s)
+}
+
+// BannedCards fetches all cards which are banned in the given game format, like the banned list
+// of Modern. An error is returned if format is empty, since the API can not tell which cards are
+// banned without a format; call BannedCards for each format to find the cards banned anywhere.
+func BannedCards(format string) ([]*Card, error) {
+	if strings.TrimSpace(format) == "" {
+		return nil, errors.New("a game format is required to fetch banned cards")
+	}
+	return NewQuery().WhereBannedIn(format).All()
+}
+
+// CommanderFormat is the name of the Commander format used by the API.
+const CommanderFormat = "Commander"
+
+// CommanderPool fetches all cards which are legal in Commander and may be played in a deck with
+// the given color identity (color names or codes), like CommanderPool("R", "G") for a Gruul
+// commander. Without colors only colorless cards are returned; unknown colors return an error
+// instead of widening the pool. The result is very large, especially for multicolored
+// identities, and the color identity is filtered after fetching. To process the pool in parts,
+// page through the same query built with WhereFormat and WhereColorIdentityWithin using
+// Query.PageResult instead.
+func CommanderPool(colors ...string) ([]*Card, error) {
+	if _, err := ParseColors(colors...); err != nil {
+		return nil, err
+	}
+	return NewQuery().WhereFormat(CommanderFormat).WhereColorIdentityWithin(colors...).All()
+}
@@ -0,0 +1,83 @@
+package mtg
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_ParseLegality(t *testing.T) {
+	Convey("When parsing a legality", t, func() {
+		Convey("known legalities should be matched case insensitive", func() {
+			l, err := ParseLegality("banned")
+			So(err, ShouldBeNil)
+			So(l, ShouldEqual, LegalityBanned)
+
+			l, err = ParseLegality("Restricted")
+			So(err, ShouldBeNil)
+			So(l, ShouldEqual, LegalityRestricted)
+		})
+
+		Convey("unknown legalities should return an error", func() {
+			_, err := ParseLegality("Suspended")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_BannedCards(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching the banned cards of a format", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?gameFormat=Modern&legality=Banned",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Splinter Twin","set":"ROE","id":"a"}]}`))
+
+		Convey("the cards banned in the format should be returned", func() {
+			cards, err := BannedCards("Modern")
+			So(err, ShouldBeNil)
+			So(cards, ShouldContainCard, "Splinter Twin")
+		})
+
+		Convey("a missing format should return an error", func() {
+			_, err := BannedCards(" ")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_CommanderPool(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching the commander pool of a color identity", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?gameFormat=Commander",
+			httpmock.NewStringResponder(200, `{"cards":[
+				{"name":"Lightning Bolt","set":"LEA","id":"a","colorIdentity":["R"]},
+				{"name":"Llanowar Elves","set":"LEA","id":"b","colorIdentity":["G"]},
+				{"name":"Counterspell","set":"LEA","id":"c","colorIdentity":["U"]},
+				{"name":"Sol Ring","set":"LEA","id":"d"}]}`))
+
+		Convey("only cards within the color identity should be returned", func() {
+			cards, err := CommanderPool("Red", "G")
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 3)
+			So(cards, ShouldContainCard, "Lightning Bolt")
+			So(cards, ShouldContainCard, "Llanowar Elves")
+			So(cards, ShouldContainCard, "Sol Ring")
+		})
+
+		Convey("unknown colors should return an error", func() {
+			_, err := CommanderPool("R", "grean")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("without colors only colorless cards should be returned", func() {
+			cards, err := CommanderPool()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+			So(cards, ShouldContainCard, "Sol Ring")
+		})
+	})
+}
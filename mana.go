@@ -0,0 +1,76 @@
+package mtg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseManaCost splits a mana cost like "{2}{W/U}{W/U}" into its symbols without braces, like
+// ["2", "W/U", "W/U"]. An error is returned if the braces are not balanced.
+func ParseManaCost(cost string) ([]string, error) {
+	var symbols []string
+	rest := strings.TrimSpace(cost)
+	for rest != "" {
+		if rest[0] != '{' {
+			return nil, fmt.Errorf("invalid mana cost %q", cost)
+		}
+		end := strings.IndexByte(rest, '}')
+		if end < 0 || strings.IndexByte(rest[1:end], '{') >= 0 {
+			return nil, fmt.Errorf("invalid mana cost %q", cost)
+		}
+		symbols = append(symbols, rest[1:end])
+		rest = rest[end+1:]
+	}
+	return symbols, nil
+}
+
+// ManaValue returns the converted mana cost of a single mana symbol as returned by
+// ParseManaCost. Numbers count their value and variable amounts like X count 0. Hybrid symbols
+// count their largest part, so {W/U} counts 1 and {2/W} counts 2; Phyrexian symbols like {W/P}
+// count 1. Half mana like {½} or {HW} counts 0.5 and all other symbols count 1.
+func ManaValue(symbol string) float64 {
+	switch symbol {
+	case "X", "Y", "Z":
+		return 0
+	case "½":
+		return 0.5
+	}
+	if n, err := strconv.ParseFloat(symbol, 64); err == nil {
+		return n
+	}
+	if strings.Contains(symbol, "/") {
+		var max float64
+		for _, part := range strings.Split(symbol, "/") {
+			if part == "P" {
+				continue
+			}
+			if v := ManaValue(part); v > max {
+				max = v
+			}
+		}
+		return max
+	}
+	if len(symbol) == 2 && symbol[0] == 'H' {
+		return 0.5
+	}
+	return 1
+}
+
+// ComputedCMC returns the converted mana cost of the card. Some records lack the cmc although
+// they have a mana cost; if CMC is zero, it is computed from the symbols of ManaCost with
+// ManaValue. CMC is returned if the mana cost can not be parsed.
+func (c *Card) ComputedCMC() float64 {
+	if c.CMC != 0 || c.ManaCost == "" {
+		return c.CMC
+	}
+	symbols, err := ParseManaCost(c.ManaCost)
+	if err != nil {
+		return c.CMC
+	}
+	var cmc float64
+	for _, s := range symbols {
+		cmc += ManaValue(s)
+	}
+	return cmc
+}
@@ -0,0 +1,58 @@
+package mtg
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_ParseManaCost(t *testing.T) {
+	Convey("When parsing a mana cost", t, func() {
+		Convey("the symbols should be returned without braces", func() {
+			symbols, err := ParseManaCost("{2}{W/U}{W/U}")
+			So(err, ShouldBeNil)
+			So(symbols, ShouldResemble, []string{"2", "W/U", "W/U"})
+		})
+		Convey("an empty cost should have no symbols", func() {
+			symbols, err := ParseManaCost("")
+			So(err, ShouldBeNil)
+			So(symbols, ShouldBeEmpty)
+		})
+		Convey("unbalanced braces should return an error", func() {
+			_, err := ParseManaCost("{2}{R")
+			So(err, ShouldNotBeNil)
+			_, err = ParseManaCost("2R")
+			So(err, ShouldNotBeNil)
+			_, err = ParseManaCost("{2{R}")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_ComputedCMC(t *testing.T) {
+	Convey("When computing the converted mana cost", t, func() {
+		Convey("the cmc should be used if it is set", func() {
+			So((&Card{ManaCost: "{3}{R}{R}", CMC: 5}).ComputedCMC(), ShouldEqual, 5)
+		})
+		Convey("a missing cmc should be computed from the mana cost", func() {
+			So((&Card{ManaCost: "{3}{R}{R}"}).ComputedCMC(), ShouldEqual, 5)
+		})
+		Convey("X should count 0", func() {
+			So((&Card{ManaCost: "{X}{R}"}).ComputedCMC(), ShouldEqual, 1)
+			So((&Card{ManaCost: "{X}{X}{G}"}).ComputedCMC(), ShouldEqual, 1)
+		})
+		Convey("hybrid symbols should count their largest part", func() {
+			So((&Card{ManaCost: "{W/U}{W/U}"}).ComputedCMC(), ShouldEqual, 2)
+			So((&Card{ManaCost: "{2/W}{2/W}{2/W}"}).ComputedCMC(), ShouldEqual, 6)
+			So((&Card{ManaCost: "{1}{G/P}"}).ComputedCMC(), ShouldEqual, 2)
+		})
+		Convey("half mana should count 0.5", func() {
+			So((&Card{ManaCost: "{½}"}).ComputedCMC(), ShouldEqual, 0.5)
+			So((&Card{ManaCost: "{HW}"}).ComputedCMC(), ShouldEqual, 0.5)
+		})
+		Convey("cards without mana cost should count 0", func() {
+			So((&Card{}).ComputedCMC(), ShouldEqual, 0)
+			So((&Card{ManaCost: "{R"}).ComputedCMC(), ShouldEqual, 0)
+		})
+	})
+}
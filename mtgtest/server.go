@@ -0,0 +1,286 @@
+// Package mtgtest provides a fake of the magicthegathering.io API which can be used to test code
+// using the mtg package without network access.
+//
+//	srv := mtgtest.NewServer(cards)
+//	defer srv.Close()
+//	mtg.Configure(mtg.WithBaseURL(srv.BaseURL()))
+package mtgtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+
+	mtg "github.com/ivanbc613/mtg-sdk-go"
+)
+
+const (
+	// DefaultPageSize is the page size used for cards if the request does not contain one.
+	DefaultPageSize = 100
+	// DefaultSetPageSize is the page size used for sets if the request does not contain one.
+	DefaultSetPageSize = 500
+)
+
+// Server is an httptest.Server which serves the cards, sets and booster endpoints of the API for
+// a fixed list of cards and sets.
+//
+// Like the API, filter values can be combined with "|" (OR) and "," (AND) and are compared case
+// insensitive. The name, setName, type, artist and text filters match if the card field contains
+// the value, unless the value is wrapped in double quotes. The set, rarity, types, supertypes,
+// subtypes, layout, colors and colorIdentity filters must match exactly. All other parameters are
+// ignored. Random queries return the requested number of cards in random order.
+type Server struct {
+	*httptest.Server
+
+	// PageSize is used for requests without a pageSize parameter.
+	PageSize int
+
+	cards []*mtg.Card
+	sets  []*mtg.Set
+}
+
+// NewServer starts a Server serving the given cards. If no sets are given, the sets are derived
+// from the set codes and set names of the cards.
+func NewServer(cards []*mtg.Card, sets ...*mtg.Set) *Server {
+	if len(sets) == 0 {
+		sets = setsOf(cards)
+	}
+	s := &Server{
+		PageSize: DefaultPageSize,
+		cards:    cards,
+		sets:     sets,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/cards", s.handleCards)
+	mux.HandleFunc("/v1/cards/", s.handleCard)
+	mux.HandleFunc("/v1/sets", s.handleSets)
+	mux.HandleFunc("/v1/sets/", s.handleSet)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// BaseURL returns the URL which must be passed to mtg.WithBaseURL to use the Server.
+func (s *Server) BaseURL() string {
+	return s.URL + "/v1/"
+}
+
+func setsOf(cards []*mtg.Card) []*mtg.Set {
+	var sets []*mtg.Set
+	known := make(map[mtg.SetCode]bool)
+	for _, c := range cards {
+		if c.Set == "" || known[c.Set] {
+			continue
+		}
+		known[c.Set] = true
+		sets = append(sets, &mtg.Set{SetCode: c.Set, Name: c.SetName})
+	}
+	return sets
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int) {
+	writeJSON(w, status, mtg.ServerError{
+		Status:  strconv.Itoa(status),
+		Message: http.StatusText(status),
+	})
+}
+
+type field struct {
+	values []string
+	// partial fields match if a value contains the filter, all others must be equal to it.
+	partial bool
+}
+
+func cardFields(c *mtg.Card) map[string]field {
+	return map[string]field{
+		"name":          {[]string{c.Name}, true},
+		"set":           {[]string{string(c.Set)}, false},
+		"setName":       {[]string{c.SetName}, true},
+		"rarity":        {[]string{c.Rarity}, false},
+		"type":          {[]string{c.Type}, true},
+		"types":         {c.Types, false},
+		"supertypes":    {c.Supertypes, false},
+		"subtypes":      {c.Subtypes, false},
+		"layout":        {[]string{c.Layout}, false},
+		"artist":        {[]string{c.Artist}, true},
+		"text":          {[]string{c.Text}, true},
+		"colors":        {c.Colors, false},
+		"colorIdentity": {c.ColorIdentity, false},
+	}
+}
+
+func setFields(s *mtg.Set) map[string]field {
+	return map[string]field{
+		"name":  {[]string{s.Name}, true},
+		"block": {[]string{s.Block}, true},
+	}
+}
+
+func (f field) matches(filter string) bool {
+	partial := f.partial
+	if len(filter) > 1 && strings.HasPrefix(filter, `"`) && strings.HasSuffix(filter, `"`) {
+		filter = strings.Trim(filter, `"`)
+		partial = false
+	}
+	filter = strings.ToLower(filter)
+	for _, v := range f.values {
+		v = strings.ToLower(v)
+		if v == filter || (partial && strings.Contains(v, filter)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(fields map[string]field, query url.Values) bool {
+	for param, f := range fields {
+		filter := query.Get(param)
+		if filter == "" {
+			continue
+		}
+		for _, and := range strings.Split(filter, ",") {
+			found := false
+			for _, or := range strings.Split(and, "|") {
+				if f.matches(or) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func intParam(query url.Values, name string, def int) int {
+	if v, err := strconv.Atoi(query.Get(name)); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
+
+// paginate returns the requested page of n items and sets the Link and Total-Count headers.
+func paginate(w http.ResponseWriter, r *http.Request, n int, defPageSize int) (from, to int) {
+	query := r.URL.Query()
+	page := intParam(query, "page", 1)
+	pageSize := intParam(query, "pageSize", defPageSize)
+	lastPage := (n + pageSize - 1) / pageSize
+	if lastPage == 0 {
+		lastPage = 1
+	}
+
+	pageUrl := func(p int) string {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(p))
+		return fmt.Sprintf("http://%s%s?%s", r.Host, r.URL.Path, q.Encode())
+	}
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageUrl(1))}
+	if page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageUrl(page-1)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageUrl(lastPage)))
+	if page < lastPage {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageUrl(page+1)))
+	}
+	w.Header().Set("Link", strings.Join(links, ", "))
+	w.Header().Set("Total-Count", strconv.Itoa(n))
+
+	from = (page - 1) * pageSize
+	if from > n {
+		from = n
+	}
+	to = from + pageSize
+	if to > n {
+		to = n
+	}
+	return from, to
+}
+
+func (s *Server) handleCards(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var cards []*mtg.Card
+	for _, c := range s.cards {
+		if matches(cardFields(c), query) {
+			cards = append(cards, c)
+		}
+	}
+
+	if query.Get("random") == "true" {
+		rand.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+		if count := intParam(query, "pageSize", s.PageSize); count < len(cards) {
+			cards = cards[:count]
+		}
+		writeJSON(w, http.StatusOK, map[string][]*mtg.Card{"cards": cards})
+		return
+	}
+
+	from, to := paginate(w, r, len(cards), s.PageSize)
+	writeJSON(w, http.StatusOK, map[string][]*mtg.Card{"cards": cards[from:to]})
+}
+
+func (s *Server) handleCard(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/v1/cards/")
+	for _, c := range s.cards {
+		if string(c.Id) == id || (c.MultiverseId != "" && c.MultiverseId == id) {
+			writeJSON(w, http.StatusOK, map[string]*mtg.Card{"card": c})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound)
+}
+
+func (s *Server) handleSets(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var sets []*mtg.Set
+	for _, set := range s.sets {
+		if matches(setFields(set), query) {
+			sets = append(sets, set)
+		}
+	}
+
+	from, to := paginate(w, r, len(sets), DefaultSetPageSize)
+	writeJSON(w, http.StatusOK, map[string][]*mtg.Set{"sets": sets[from:to]})
+}
+
+func (s *Server) handleSet(w http.ResponseWriter, r *http.Request) {
+	code := strings.TrimPrefix(r.URL.Path, "/v1/sets/")
+	booster := strings.HasSuffix(code, "/booster")
+	code = strings.TrimSuffix(code, "/booster")
+
+	for _, set := range s.sets {
+		if !strings.EqualFold(string(set.SetCode), code) {
+			continue
+		}
+		if !booster {
+			writeJSON(w, http.StatusOK, map[string]*mtg.Set{"set": set})
+			return
+		}
+
+		var cards []*mtg.Card
+		for _, c := range s.cards {
+			if set.Contains(c) {
+				cards = append(cards, c)
+			}
+		}
+		rand.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+		if len(cards) > 15 {
+			cards = cards[:15]
+		}
+		writeJSON(w, http.StatusOK, map[string][]*mtg.Card{"cards": cards})
+		return
+	}
+	writeError(w, http.StatusNotFound)
+}
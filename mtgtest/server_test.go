@@ -0,0 +1,106 @@
+package mtgtest
+
+import (
+	"errors"
+	"testing"
+
+	mtg "github.com/ivanbc613/mtg-sdk-go"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testCards = []*mtg.Card{
+	{Name: "Mardu Heart-Piercer", Set: "KTK", SetName: "Khans of Tarkir", Rarity: "Uncommon", Colors: []string{"Red"}, Id: "a1", MultiverseId: "386593"},
+	{Name: "Sarkhan, the Dragonspeaker", Set: "KTK", SetName: "Khans of Tarkir", Rarity: "Mythic Rare", Colors: []string{"Red"}, Id: "a2"},
+	{Name: "Crater's Claws", Set: "KTK", SetName: "Khans of Tarkir", Rarity: "Rare", Colors: []string{"Red"}, Id: "a3"},
+	{Name: "Lightning Bolt", Set: "M10", SetName: "Magic 2010", Rarity: "Common", Colors: []string{"Red"}, Id: "b1"},
+	{Name: "Serra Angel", Set: "M10", SetName: "Magic 2010", Rarity: "Uncommon", Colors: []string{"White"}, Id: "b2"},
+}
+
+func Test_Server(t *testing.T) {
+	Convey("With a test server", t, func() {
+		srv := NewServer(testCards)
+		defer srv.Close()
+		mtg.Configure(mtg.WithBaseURL(srv.BaseURL()))
+		defer mtg.Configure(mtg.WithBaseURL(mtg.DefaultBaseURL))
+
+		Convey("all matching cards should be found", func() {
+			cards, err := mtg.NewQuery().Where(mtg.CardColors, "red").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 4)
+		})
+
+		Convey("All should follow the pagination links", func() {
+			srv.PageSize = 2
+			cards, err := mtg.NewQuery().All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 5)
+		})
+
+		Convey("a page should report the total count", func() {
+			cards, total, err := mtg.NewQuery().Where(mtg.CardSet, "KTK").PageS(2, 2)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 3)
+			So(cards, ShouldHaveLength, 1)
+			So(cards[0].Name, ShouldEqual, "Crater's Claws")
+		})
+
+		Convey("filters should support OR and AND", func() {
+			cards, err := mtg.NewQuery().Where(mtg.CardRarity, "rare|common").Where(mtg.CardSet, "M10").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+			So(cards[0].Name, ShouldEqual, "Lightning Bolt")
+		})
+
+		Convey("names should be matched partial or exact", func() {
+			cards, err := mtg.NewQuery().WhereName("serra", mtg.MatchContains).All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+
+			cards, err = mtg.NewQuery().WhereName("serra", mtg.MatchExact).All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldBeEmpty)
+
+			cards, err = mtg.NewQuery().WhereName("serra angel", mtg.MatchExact).All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+		})
+
+		Convey("random cards should be limited to the count", func() {
+			cards, err := mtg.NewQuery().Random(2)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+		})
+
+		Convey("cards should be fetched by id", func() {
+			card, err := mtg.CardId("b1").Fetch()
+			So(err, ShouldBeNil)
+			So(card.Name, ShouldEqual, "Lightning Bolt")
+
+			card, err = mtg.MultiverseId(386593).Fetch()
+			So(err, ShouldBeNil)
+			So(card.Name, ShouldEqual, "Mardu Heart-Piercer")
+
+			_, err = mtg.CardId("unknown").Fetch()
+			So(errors.Is(err, mtg.ErrCardNotFound), ShouldBeTrue)
+		})
+
+		Convey("the sets should be derived from the cards", func() {
+			sets, err := mtg.NewSetQuery().All()
+			So(err, ShouldBeNil)
+			So(sets, ShouldHaveLength, 2)
+
+			set, err := mtg.SetCode("ktk").Fetch()
+			So(err, ShouldBeNil)
+			So(set.Name, ShouldEqual, "Khans of Tarkir")
+
+			_, err = mtg.SetCode("XXX").Fetch()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("boosters should contain cards of the set", func() {
+			cards, err := mtg.SetCode("M10").GenerateBooster()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+		})
+	})
+}
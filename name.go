@@ -0,0 +1,68 @@
+package mtg
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var nameReplacer = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"æ", "ae", "ç", "c",
+	"è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i",
+	"ñ", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o", "œ", "oe",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u",
+	"ý", "y", "ÿ", "y", "ß", "ss",
+	"’", "'", "‘", "'",
+)
+
+// splitSeparator matches the separator of the halves of split cards in all its spellings, like
+// "Fire // Ice", "Fire//Ice" or "Fire / Ice".
+var splitSeparator = regexp.MustCompile(`\s*/+\s*`)
+
+// NormalizeName returns the name in a form which is suited to compare names entered by users:
+// the name is lowercased, accents are removed ("Lim-Dûl" becomes "lim-dul", "Æther" becomes
+// "aether"), typographic apostrophes are replaced, whitespace is collapsed and the halves of
+// split cards are always separated by " // ".
+func NormalizeName(name string) string {
+	name = nameReplacer.Replace(strings.ToLower(name))
+	name = splitSeparator.ReplaceAllString(name, " // ")
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// MatchName reports whether both names are the same after normalizing them with NormalizeName.
+func MatchName(a, b string) bool {
+	return NormalizeName(a) == NormalizeName(b)
+}
+
+// BuildNameIndex maps the names of the cards, normalized with NormalizeName, to the cards. If a
+// name is printed several times, the most recent printing is kept. A printing is dated by the
+// release date of its set, which is looked up in sets (like those returned by SetQuery.All), or
+// by the ReleaseDate of the card if its set is not given; only promo cards have such a date.
+// Printings with a date win over printings without one, and of printings released on the same
+// day or without any date the first one in cards is kept.
+func BuildNameIndex(cards []*Card, sets ...*Set) map[string]*Card {
+	released := make(map[SetCode]time.Time, len(sets))
+	for _, s := range sets {
+		if t, err := s.Released(); err == nil {
+			released[s.SetCode] = t
+		}
+	}
+	date := func(c *Card) time.Time {
+		if t, ok := released[c.Set]; ok {
+			return t
+		}
+		return time.Time(c.ReleaseDate)
+	}
+
+	index := make(map[string]*Card, len(cards))
+	for _, c := range cards {
+		name := NormalizeName(c.Name)
+		if prev, ok := index[name]; !ok || date(c).After(date(prev)) {
+			index[name] = c
+		}
+	}
+	return index
+}
@@ -0,0 +1,72 @@
+package mtg
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_NormalizeName(t *testing.T) {
+	Convey("When normalizing card names", t, func() {
+		Convey("accents should be removed", func() {
+			So(NormalizeName("Lim-Dûl the Necromancer"), ShouldEqual, "lim-dul the necromancer")
+			So(NormalizeName("Jötun Grunt"), ShouldEqual, "jotun grunt")
+		})
+
+		Convey("ligatures should be expanded", func() {
+			So(NormalizeName("Æther Vial"), ShouldEqual, "aether vial")
+			So(NormalizeName("Æther Vial"), ShouldEqual, NormalizeName("Aether Vial"))
+		})
+
+		Convey("split separators should be normalized", func() {
+			So(NormalizeName("Fire // Ice"), ShouldEqual, "fire // ice")
+			So(NormalizeName("Fire//Ice"), ShouldEqual, "fire // ice")
+			So(NormalizeName("fire / ice"), ShouldEqual, "fire // ice")
+		})
+
+		Convey("whitespace and apostrophes should be normalized", func() {
+			So(NormalizeName("  Urza’s   Saga "), ShouldEqual, "urza's saga")
+		})
+	})
+
+	Convey("When matching card names", t, func() {
+		So(MatchName("Lim-Dul the Necromancer", "Lim-Dûl the Necromancer"), ShouldBeTrue)
+		So(MatchName("aether vial", "Æther Vial"), ShouldBeTrue)
+		So(MatchName("Fire/Ice", "Fire // Ice"), ShouldBeTrue)
+		So(MatchName("Fire", "Fire // Ice"), ShouldBeFalse)
+	})
+}
+
+func Test_BuildNameIndex(t *testing.T) {
+	Convey("When building a name index", t, func() {
+		alpha := &Card{Name: "Lightning Bolt", Set: "LEA", Id: "a"}
+		m10 := &Card{Name: "Lightning Bolt", Set: "M10", Id: "b"}
+		aether := &Card{Name: "Æther Vial", Set: "DST", Id: "c"}
+		sets := []*Set{
+			{SetCode: "LEA", ReleaseDate: "1993-08-05"},
+			{SetCode: "M10", ReleaseDate: "2009-07-17"},
+		}
+
+		Convey("the cards should be found by their normalized name", func() {
+			index := BuildNameIndex([]*Card{alpha, aether}, sets...)
+			So(index, ShouldHaveLength, 2)
+			So(index["lightning bolt"], ShouldEqual, alpha)
+			So(index[NormalizeName("Aether Vial")], ShouldEqual, aether)
+		})
+
+		Convey("the most recent printing should be kept", func() {
+			So(BuildNameIndex([]*Card{m10, alpha}, sets...)["lightning bolt"], ShouldEqual, m10)
+			So(BuildNameIndex([]*Card{alpha, m10}, sets...)["lightning bolt"], ShouldEqual, m10)
+		})
+
+		Convey("the release date of promo cards should be used without sets", func() {
+			promo := &Card{Name: "Lightning Bolt", Set: "PRM", Id: "d", ReleaseDate: Date(time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC))}
+			So(BuildNameIndex([]*Card{alpha, promo})["lightning bolt"], ShouldEqual, promo)
+		})
+
+		Convey("the first card should be kept without any dates", func() {
+			So(BuildNameIndex([]*Card{alpha, m10})["lightning bolt"], ShouldEqual, alpha)
+		})
+	})
+}
@@ -0,0 +1,54 @@
+package mtg
+
+import "strconv"
+
+// PowerToughness is the parsed power and toughness of a creature. The raw strings stay available
+// as Card.Power and Card.Toughness.
+type PowerToughness struct {
+	// Power is the numeric part of the power, like 1 for "1+*". Half values like ".5" are kept.
+	Power float64
+	// Toughness is the numeric part of the toughness.
+	Toughness float64
+	// VariablePower is set if the power is not fixed, like "*" or "1+*". Powers without any
+	// number are variable with a Power of zero.
+	VariablePower bool
+	// VariableToughness is set if the toughness is not fixed.
+	VariableToughness bool
+}
+
+// String returns the power and toughness like printed on the card, for example "2/3" or "*/1+*".
+func (pt PowerToughness) String() string {
+	return formatStat(pt.Power, pt.VariablePower) + "/" + formatStat(pt.Toughness, pt.VariableToughness)
+}
+
+// PT returns the parsed power and toughness of the card. Only creatures have a power and
+// toughness; for all other cards, and creatures missing either value, PT returns false.
+func (c *Card) PT() (PowerToughness, bool) {
+	if c.Power == "" || c.Toughness == "" || !c.TypeLine().hasType("Creature") {
+		return PowerToughness{}, false
+	}
+	var pt PowerToughness
+	pt.Power, pt.VariablePower = parseStat(c.Power)
+	pt.Toughness, pt.VariableToughness = parseStat(c.Toughness)
+	return pt, true
+}
+
+// parseStat parses a power or toughness and reports whether it is variable.
+func parseStat(s string) (float64, bool) {
+	n, rest, ok := leadingNumber(s)
+	if !ok {
+		return 0, true
+	}
+	return n, rest != ""
+}
+
+// formatStat formats a power or toughness parsed by parseStat.
+func formatStat(n float64, variable bool) string {
+	switch {
+	case variable && n == 0:
+		return "*"
+	case variable:
+		return strconv.FormatFloat(n, 'f', -1, 64) + "+*"
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,41 @@
+package mtg
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_PT(t *testing.T) {
+	Convey("When reading the power and toughness of a card", t, func() {
+		Convey("fixed values should be parsed", func() {
+			pt, ok := (&Card{Types: []string{"Creature"}, Power: "3", Toughness: "2"}).PT()
+			So(ok, ShouldBeTrue)
+			So(pt, ShouldResemble, PowerToughness{Power: 3, Toughness: 2})
+			So(pt.String(), ShouldEqual, "3/2")
+		})
+
+		Convey("variable values should be flagged", func() {
+			pt, ok := (&Card{Type: "Creature — Avatar", Power: "*", Toughness: "1+*"}).PT()
+			So(ok, ShouldBeTrue)
+			So(pt, ShouldResemble, PowerToughness{Power: 0, Toughness: 1, VariablePower: true, VariableToughness: true})
+			So(pt.String(), ShouldEqual, "*/1+*")
+		})
+
+		Convey("half values should be kept", func() {
+			pt, ok := (&Card{Types: []string{"Creature"}, Power: ".5", Toughness: ".5"}).PT()
+			So(ok, ShouldBeTrue)
+			So(pt.Power, ShouldEqual, 0.5)
+			So(pt.String(), ShouldEqual, "0.5/0.5")
+		})
+
+		Convey("non-creatures should have no power and toughness", func() {
+			_, ok := (&Card{Types: []string{"Artifact"}, Subtypes: []string{"Vehicle"}, Power: "3", Toughness: "3"}).PT()
+			So(ok, ShouldBeFalse)
+
+			pt, ok := (&Card{Types: []string{"Instant"}}).PT()
+			So(ok, ShouldBeFalse)
+			So(pt, ShouldResemble, PowerToughness{})
+		})
+	})
+}
@@ -1,23 +1,68 @@
 package mtg
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strconv"
 	"strings"
 )
 
 const (
-	queryUrl = "https://api.magicthegathering.io/v1/"
+	// DefaultBaseURL is the URL of the public API which is used unless configured otherwise with WithBaseURL.
+	DefaultBaseURL = "https://api.magicthegathering.io/v1/"
+	// DefaultCardsPath is the path of the cards endpoint relative to the base URL.
+	DefaultCardsPath = "cards"
+	// DefaultSetsPath is the path of the sets endpoint relative to the base URL.
+	DefaultSetsPath = "sets"
 )
 
-var (
-	linkRE = regexp.MustCompile(`<(.*)>; rel="(.*)"`)
+type CardColumn string
+
+// MatchMode defines how text filters like WhereName compare the given value.
+type MatchMode int
+
+const (
+	// MatchContains matches all cards containing the value. This is the default of the API,
+	// so searching "Bolt" finds Lightning Bolt, Boltwing Marauder and many more.
+	MatchContains MatchMode = iota
+	// MatchExact only matches cards with exactly the given value (still case insensitive).
+	// The API matches exactly if the value is wrapped in double quotes; since it does not do so
+	// reliably, the helpers taking a MatchMode filter the fetched cards as well.
+	MatchExact
 )
 
-type CardColumn string
+func (m MatchMode) value(v string) string {
+	if m == MatchExact {
+		return `"` + v + `"`
+	}
+	return v
+}
+
+// Comparison is the operator used to compare numeric columns like the loyalty with a value.
+type Comparison string
+
+const (
+	// CompareEqual matches values equal to the given value. The API has no operator for
+	// equality, a plain number like "3" matches the values equal to it.
+	CompareEqual = Comparison("")
+	// CompareGreater matches values greater than the given value.
+	CompareGreater = Comparison("gt")
+	// CompareGreaterOrEqual matches values greater than or equal to the given value.
+	CompareGreaterOrEqual = Comparison("gte")
+	// CompareLess matches values less than the given value.
+	CompareLess = Comparison("lt")
+	// CompareLessOrEqual matches values less than or equal to the given value.
+	CompareLessOrEqual = Comparison("lte")
+)
+
+// value returns the query value comparing with v, like "gte4".
+func (c Comparison) value(v string) string {
+	return string(c) + v
+}
 
 var (
 	// CardName is the column for the name property.
@@ -54,7 +99,9 @@ var (
 	// The set the card belongs to (set code).
 	CardSet = CardColumn("set")
 	// CardSetName is the column for the setName property.
-	// The set the card belongs to.
+	// The set the card belongs to. The API matches partial names, so "Khans" finds the cards of
+	// Khans of Tarkir; wrap the name in double quotes (or use WhereSetName with MatchExact) to
+	// match the full name only.
 	CardSetName = CardColumn("setName")
 	// CardText is the column for the text property.
 	// The oracle text of the card. May contain mana symbols and other symbols.
@@ -89,27 +136,250 @@ var (
 	// CardLegality is the column for the legality property.
 	// The legality of the card for a given format, such as Legal, Banned or Restricted.
 	CardLegality = CardColumn("legality")
+	// CardMultiverseId is the column for the multiverseid property.
+	// The multiverseid of the card on Wizard’s Gatherer web page.
+	CardMultiverseId = CardColumn("multiverseid")
 )
 
 // Query interface can be used to query multiple cards by their properties
+//
+// The API always returns complete cards; it has no parameter to select only some fields, so
+// there is no way to reduce the size of the responses by leaving out fields like the text or
+// the flavor. Use a bigger page size (PageS) to reduce the number of requests instead.
+//
+// A query which matches no cards is not an error: All, Page, PageS and Random return no cards,
+// a total card count of zero and a nil error. An error always means that the cards could not be
+// fetched, so "no matches" and "failed" can be told apart by the error alone.
+//
+// A Query is changed in place: Where, OrderBy and all other filters change the query they are
+// called on and return that same query, they do not create a new one. So two variables holding
+// the same query always share all filters:
+//
+//	base := NewQuery().Where(CardSet, "KTK")
+//	rares := base.Where(CardRarity, "Rare")      // base is filtered by rarity as well
+//	commons := base.Where(CardRarity, "Common")  // rares, commons and base are the same query
+//
+// To branch a query, call Copy for every branch: base.Copy().Where(CardRarity, "Rare"). A copy
+// shares nothing with the original, so later changes of one never affect the other. Queries are
+// not safe for concurrent use if one of the goroutines changes the query.
 type Query interface {
-	// Where filters the given column by the given value
+	// Where filters the given column by the given value. Calling Where again for the same column
+	// does not replace the first value, instead both values are joined with a comma which the API
+	// treats as logical AND: Where(CardTypes, "Creature").Where(CardTypes, "Artifact") finds
+	// artifact creatures.
 	Where(column CardColumn, qry string) Query
+	// WhereName filters the cards by name. With MatchContains all cards containing the name are found,
+	// MatchExact only finds cards with exactly the given name (compared with MatchName). Like
+	// WhereArtist, MatchExact asks the API for an exact match and filters the fetched cards as
+	// well, so "Bolt" does not find Lightning Bolt even if the API matches parts of the name.
+	// Like with Where, a card must match every name given by repeated calls.
+	WhereName(name string, mode MatchMode) Query
+	// WhereNameExact filters the cards with exactly the given name. It is a shortcut for
+	// WhereName(name, MatchExact).
+	WhereNameExact(name string) Query
+	// WhereArtist filters the cards by artist. With MatchContains all cards whose artist contains
+	// the value are found, so "Rush" finds the cards of Christopher Rush. With MatchExact only
+	// the cards of exactly that artist (case insensitive) are kept, so "John" does not find the
+	// cards of John Avon or Johnson; besides asking the API for an exact match, the fetched
+	// cards are filtered like WhereColorIdentityWithin, so pages may contain fewer cards than
+	// requested. See also Artists.
+	WhereArtist(artist string, mode MatchMode) Query
+	// WhereArtistExact filters the cards of exactly the given artist. It is a shortcut for
+	// WhereArtist(artist, MatchExact).
+	WhereArtistExact(artist string) Query
+	// WhereSetName filters the cards by the name of their set without resolving its code first.
+	// With MatchContains all sets whose name contains the value are searched, so "Khans" finds
+	// the cards of Khans of Tarkir, MatchExact only searches the set with exactly that name
+	// (case insensitive). Like WhereArtist, MatchExact filters the fetched cards as well.
+	WhereSetName(name string, mode MatchMode) Query
+	// WhereSetNameContains filters the cards of all sets whose name contains the value. It is a
+	// shortcut for WhereSetName(name, MatchContains).
+	WhereSetNameContains(name string) Query
+	// WhereTextContains filters the cards whose oracle text contains the phrase. The phrase is
+	// matched as a whole including its spaces, so "draw a card" does not find "draw two cards".
+	// Spaces are encoded as "+" in the URL, which the API decodes to spaces again. Since the
+	// API uses "," and "|" to combine values, phrases must not contain them.
+	WhereTextContains(phrase string) Query
+	// WhereTextAll filters the cards whose oracle text contains all of the phrases.
+	WhereTextAll(phrases ...string) Query
+	// WhereTextAny filters the cards whose oracle text contains at least one of the phrases.
+	WhereTextAny(phrases ...string) Query
+	// WhereColorIdentityWithin only keeps cards whose color identity is a subset of the given
+	// colors (color names or codes), like the cards allowed in a Commander deck. Without colors
+	// only colorless cards are kept. An unknown color (like a misspelled "grean") can not be
+	// matched, so the filter then keeps no cards at all instead of guessing; use ParseColors to
+	// validate colors given by users. Since the API can not express this filter, it is applied
+	// to the fetched cards, so pages may contain fewer cards than requested and the total card
+	// count of Page and PageS includes cards which were filtered out. RawAll and FetchLink are
+	// not filtered.
+	WhereColorIdentityWithin(colors ...string) Query
+	// WhereColorless only keeps colorless nonland cards, like artifacts and Eldrazi. Lands are
+	// colorless as well but are left out, since they are rarely wanted in such searches; combine
+	// Where(CardTypes, "Land") with WhereColorIdentityWithin() to find colorless lands. A
+	// colorless card may still have a colored color identity (like an artifact with an ability
+	// costing {R}); use WhereColorIdentityWithin() without colors to exclude those as well. The
+	// API can not filter for cards without colors, so like WhereColorIdentityWithin the filter
+	// is applied to the fetched cards.
+	WhereColorless() Query
+	// WhereMulticolor only keeps cards with two or more colors, the "gold" cards. Hybrid cards
+	// like Boros Guildmage ({R/W}{R/W}) have all colors of their hybrid symbols and are kept as
+	// well; colorless cards never are. The API can not filter by the number of colors, so like
+	// WhereColorIdentityWithin the filter is applied to the fetched cards. Combine it with
+	// Where(CardColors, ...) to reduce the cards which are fetched.
+	WhereMulticolor() Query
+	// WhereHasText only keeps cards with rules text, leaving out vanilla creatures and basic
+	// lands. The API can not filter for a non-empty text, so like WhereColorIdentityWithin the
+	// filter is applied to the fetched cards. A single keyword like Flying counts as text, so
+	// creatures with only keywords are kept. See HasText for cards which were already fetched.
+	WhereHasText() Query
+	// WhereExcludeTokens removes tokens (cards with the layout "token") from the results. The API
+	// can not exclude a layout, so like WhereColorIdentityWithin the filter is applied to the
+	// fetched cards.
+	WhereExcludeTokens() Query
+	// WhereSupertype filters the cards which have all of the given supertypes. Without any
+	// supertype the query is not changed. Like all type filters it only matches the type line, so
+	// a type which is only mentioned in the text of a card (like in reminder text) is not found.
+	WhereSupertype(supertypes ...Supertype) Query
+	// WhereType filters the cards which have all of the given types, so
+	// WhereType(TypeArtifact, TypeCreature) finds artifact creatures. Without any type the query
+	// is not changed.
+	WhereType(types ...Type) Query
+	// WhereTypeAny filters the cards which have at least one of the given types, so
+	// WhereTypeAny(TypeInstant, TypeSorcery) finds all instants and sorceries. Without any type
+	// the query is not changed.
+	WhereTypeAny(types ...Type) Query
+	// WhereSubtype filters the cards which have all of the given subtypes. Without any subtype
+	// the query is not changed.
+	WhereSubtype(subtypes ...Subtype) Query
+	// WhereSet filters the cards by the code of the given set. A nil set does not change the query.
+	WhereSet(set *Set) Query
+	// WhereSets filters the cards which were printed in any of the given sets, for example all
+	// sets of a block. The codes are sent as given and empty codes are ignored; without any code
+	// the query is not changed.
+	WhereSets(codes ...SetCode) Query
+	// WhereMultiverseIds filters the cards with any of the given multiverse ids. Zero ids are
+	// ignored; without any id the query is not changed. The API rejects URLs which are too long,
+	// so use FetchByMultiverseIds for long lists of ids.
+	WhereMultiverseIds(ids ...MultiverseId) Query
+	// WhereRarity filters the cards by the given rarity
+	WhereRarity(rarity Rarity) Query
+	// WhereRarityIn filters the cards which have any of the given rarities, like
+	// WhereRarityIn(RarityRare, RarityMythicRare). RarityUnknown is ignored; without any
+	// rarity the query is not changed.
+	WhereRarityIn(rarities ...Rarity) Query
+	// WhereRarityAtLeast filters the cards of the given rarity or better, so RarityRare finds
+	// rare and mythic rare cards (see Rarity.AtLeast). Special cards and basic lands are never
+	// found for Common through Mythic Rare; RaritySpecial and RarityBasicLand only find their
+	// own rarity, and RarityUnknown does not change the query.
+	WhereRarityAtLeast(rarity Rarity) Query
+	// WhereLoyalty filters the planeswalkers by their starting loyalty, for example
+	// WhereLoyalty(CompareGreaterOrEqual, 4). The comparison is made by the API; cards with a
+	// loyalty which is no number (like "X") are never matched by a comparison.
+	WhereLoyalty(op Comparison, loyalty int) Query
+	// WhereLoyaltyEqual filters the planeswalkers with exactly the given starting loyalty. It is
+	// a shortcut for WhereLoyalty(CompareEqual, loyalty).
+	WhereLoyaltyEqual(loyalty int) Query
+	// WhereCMCEqual filters the cards with exactly the given converted mana cost, like
+	// Where(CardCMC, "3").
+	WhereCMCEqual(cmc int) Query
+	// WherePowerEqual filters the creatures with exactly the given power. Cards with a power
+	// which is no number (like "*") are not matched.
+	WherePowerEqual(power int) Query
+	// WhereToughnessEqual filters the creatures with exactly the given toughness. Cards with a
+	// toughness which is no number (like "*") are not matched.
+	WhereToughnessEqual(toughness int) Query
+	// WhereRaw sets the query parameter key to value, replacing any previous value. This is an
+	// escape hatch for parameters of the API which are not supported by this package yet;
+	// prefer Where and the other helpers whenever possible.
+	WhereRaw(key, value string) Query
+	// WhereForeignName filters by the name of the card in the given language. The API only searches
+	// foreign names if the language is given as well, so both parameters are required.
+	WhereForeignName(name, language string) Query
+	// WhereFormat filters the cards which are legal in the given game format, like Standard or
+	// Commander. The API defaults the legality to Legal if only the format is given.
+	WhereFormat(format string) Query
+	// WhereFormatLegality filters the cards which have the given legality in the given game
+	// format, for example the cards banned in Modern. Use ParseLegality to validate legalities
+	// given as strings.
+	WhereFormatLegality(format string, legality LegalityStatus) Query
+	// WhereBannedIn filters the cards which are banned in the given game format. The format is
+	// required: the API only knows the legality of a card within a format, so there is no way to
+	// find the cards banned in any format with a single query.
+	WhereBannedIn(format string) Query
+	// WhereRestrictedIn filters the cards which are restricted in the given game format, like
+	// the restricted list of Vintage. Like for WhereBannedIn the format is required.
+	WhereRestrictedIn(format string) Query
 	// Sorts the query results by the given column
 	OrderBy(column CardColumn) Query
 
-	// Creates a copy of this query
+	// Creates a copy of this query. The copy and the original are independent: filters added to
+	// one of them do not change the other.
 	Copy() Query
+	// Merge adds all filters and the ordering of other to this query. If both queries filter
+	// the same column, the value of other replaces the value of this query.
+	Merge(other Query) Query
 
-	// Fetches all cards matching the current query
+	// Fetches all cards matching the current query. The cards are fetched in pages of
+	// DefaultPageSize cards unless configured otherwise with WithPageSize. The pages are
+	// followed by the next links of the API; a next link which does not advance the page, like a
+	// link to the current page, ends the fetching and the cards fetched so far are returned.
 	All(debug ...bool) ([]*Card, error)
+	// Fetches all cards matching the current query like All. The requests are aborted when ctx
+	// is done. See WithDebugInfo to record the requests which were made.
+	AllContext(ctx context.Context) ([]*Card, error)
+	// Fetches all cards matching the current query like All and returns statistics about the
+	// requests which were made. The statistics are also returned if an error occurred.
+	AllStats(debug ...bool) ([]*Card, Stats, error)
+	// Fetches the cards matching the current query like All, but stops fetching further pages
+	// once max cards are collected. At most max cards are returned.
+	AllLimit(max int, debug ...bool) ([]*Card, error)
+	// Fetches only the first page of the cards All would fetch, which bounds the result of an
+	// unexpectedly broad query to a single request. Unlike AllLimit, which fetches pages until
+	// max cards are collected, FirstPage never makes more than one request, so client side
+	// filters like WhereColorIdentityWithin may leave fewer cards than the page size.
+	FirstPage(debug ...bool) ([]*Card, error)
+	// PlanQuery estimates the cost of All before running it, for example to warn before a broad
+	// query fetches thousands of cards. It fetches only the first page to read the total card
+	// count and returns the number of pages All would fetch at the configured page size, which
+	// is returned as well. Client side filters like WhereColorIdentityWithin are applied after
+	// fetching, so the estimated cards include the cards they would remove.
+	PlanQuery() (estimatedCards int, estimatedPages int, pageSize int, err error)
+	// Fetches all cards matching the current query without decoding them, for example to
+	// inspect a response which can not be decoded. The result contains the bodies of all pages
+	// exactly as the API returned them, separated by a line break, so a json.Decoder reads one
+	// response ({"cards":[...]}) after another.
+	RawAll() ([]byte, error)
+	// Fetches all cards matching the current query without decoding them like RawAll. The
+	// requests are aborted when ctx is done.
+	RawAllContext(ctx context.Context) ([]byte, error)
 
-	// Fetches the given page of cards.
+	// Fetches the given page of cards. The page size is DefaultPageSize unless configured
+	// otherwise with WithPageSize.
 	Page(pageNum int, debug ...bool) (cards []*Card, totalCardCount int, err error)
 	// Fetches one page of cards with a given page size
 	PageS(pageNum int, pageSize int, debug ...bool) (cards []*Card, totalCardCount int, err error)
-	// Fetches some random cards
+	// Fetches one page of cards like PageS and returns it together with the information needed
+	// to paginate, like the total card count and whether there is a next page.
+	PageResult(pageNum int, pageSize int, debug ...bool) (*PageResult, error)
+	// Fetches one page of cards like PageResult. The request is aborted when ctx is done.
+	PageResultContext(ctx context.Context, pageNum int, pageSize int) (*PageResult, error)
+	// Fetches one page of cards like PageS and returns the links to the related pages,
+	// which can be fetched with FetchLink.
+	PageLinks(pageNum int, pageSize int) (cards []*Card, links Links, err error)
+	// Fetches some random cards. The API returns at most MaxPageSize cards per request, so bigger
+	// counts are fetched with several requests. Since each request picks its cards independently,
+	// the cards are deduplicated by Id and further requests are made until count cards are
+	// gathered. This is best effort: if randomMaxAttempts requests in a row add no new card, for
+	// example because fewer cards match the query, the cards gathered so far are returned.
 	Random(count int, debug ...bool) ([]*Card, error)
+	// Fetches some random cards like Random. The requests are aborted when ctx is done.
+	RandomContext(ctx context.Context, count int) ([]*Card, error)
+
+	// URL returns the URL of the first request All would make, without making it. Client side
+	// filters like WhereColorIdentityWithin are not part of the URL.
+	URL() string
+	// PageURL returns the URL PageS would fetch for the given page, without fetching it.
+	PageURL(pageNum int, pageSize int) string
 }
 
 // NewQuery creates a new Query to fetch cards
@@ -119,8 +389,51 @@ func NewQuery() Query {
 
 type query map[string]string
 
-func fetchCards(url string, isDebug bool) ([]*Card, http.Header, error) {
-	resp, err := http.Get(url)
+// Stats contains statistics about the requests made while executing a query.
+type Stats struct {
+	// Requests is the number of HTTP requests which were made.
+	Requests int
+	// Bytes is the total size of all response bodies which were read.
+	Bytes int64
+}
+
+// PageResult is one page of cards returned by Query.PageResult.
+type PageResult struct {
+	// Cards are the cards of the page.
+	Cards []*Card
+	// Total is the number of cards matching the query on all pages.
+	Total int
+	// Page is the number of the page, starting at 1.
+	Page int
+	// PageSize is the requested number of cards per page.
+	PageSize int
+	// HasNext reports whether there is a page after this one.
+	HasNext bool
+}
+
+// IsEmpty reports whether the query matched no cards at all. A page after the last page has no
+// cards either, but is not empty as long as the query matched other cards.
+func (r *PageResult) IsEmpty() bool {
+	return r.Total == 0
+}
+
+type countingReader struct {
+	io.Reader
+	n *int64
+}
+
+func (r countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	*r.n += int64(n)
+	return n, err
+}
+
+// fetchCards fetches and decodes the cards of url. If stats is not nil, the request is added to it.
+func fetchCards(ctx context.Context, url string, isDebug bool, stats *Stats) ([]*Card, http.Header, error) {
+	if stats != nil {
+		stats.Requests++
+	}
+	resp, err := httpGet(ctx, url)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -136,7 +449,11 @@ func fetchCards(url string, isDebug bool) ([]*Card, http.Header, error) {
 	if err := checkError(resp); err != nil {
 		return nil, nil, err
 	}
-	cards, err := decodeCards(bdy)
+	var body io.Reader = bdy
+	if stats != nil {
+		body = countingReader{bdy, &stats.Bytes}
+	}
+	cards, err := decodeCards(body)
 	if isDebug {
 		fmt.Println("Decoded cards:")
 		fmt.Printf("%+v\n", cards)
@@ -147,95 +464,387 @@ func fetchCards(url string, isDebug bool) ([]*Card, http.Header, error) {
 	return cards, resp.Header, nil
 }
 
+// Links contains the URLs of the pages related to a fetched page, as given by the Link header
+// of the API. Relations which were not given by the API are empty.
+type Links struct {
+	First string
+	Prev  string
+	Next  string
+	Last  string
+}
+
+func parseLinks(header http.Header) Links {
+	var links Links
+	for _, value := range header.Values("Link") {
+		for _, l := range parseLinkHeader(value) {
+			for _, rel := range strings.Fields(l.params["rel"]) {
+				switch strings.ToLower(rel) {
+				case "first":
+					links.First = l.url
+				case "prev", "previous":
+					links.Prev = l.url
+				case "next":
+					links.Next = l.url
+				case "last":
+					links.Last = l.url
+				}
+			}
+		}
+	}
+	return links
+}
+
+// nextPage returns the next link of the page current, or an empty string if following it would
+// not advance: under load the API sometimes links to the current page again, which would make
+// All request the same page forever. A next link is also ignored if its page number is not
+// greater than the one of current.
+func nextPage(current, next string) string {
+	if next == "" || next == current {
+		return ""
+	}
+	page, ok := pageParam(next)
+	if !ok {
+		return next
+	}
+	if currentPage, _ := pageParam(current); page <= currentPage {
+		return ""
+	}
+	return next
+}
+
+// pageParam returns the page parameter of the URL. A URL without it is the first page.
+func pageParam(rawURL string) (int, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, false
+	}
+	page := u.Query().Get("page")
+	if page == "" {
+		return 1, false
+	}
+	n, err := strconv.Atoi(page)
+	return n, err == nil
+}
+
+type link struct {
+	url    string
+	params map[string]string
+}
+
+// parseLinkHeader parses the value of a Link header (RFC 8288) like
+// `<url>; rel="next"; title="x", <url2>; rel=last`. Malformed links are skipped.
+func parseLinkHeader(value string) []link {
+	var links []link
+	for {
+		start := strings.IndexByte(value, '<')
+		if start < 0 {
+			return links
+		}
+		end := strings.IndexByte(value[start:], '>')
+		if end < 0 {
+			return links
+		}
+		l := link{
+			url:    strings.TrimSpace(value[start+1 : start+end]),
+			params: make(map[string]string),
+		}
+		value = value[start+end+1:]
+
+		// parameters follow until the next comma which is not part of a quoted string
+		for {
+			value = strings.TrimLeft(value, " \t")
+			if !strings.HasPrefix(value, ";") {
+				break
+			}
+			value = strings.TrimLeft(value[1:], " \t")
+
+			i := strings.IndexAny(value, "=;,")
+			if i < 0 || value[i] != '=' {
+				// parameter without a value
+				if i < 0 {
+					i = len(value)
+				}
+				l.params[strings.ToLower(strings.TrimSpace(value[:i]))] = ""
+				value = value[i:]
+				continue
+			}
+			key := strings.ToLower(strings.TrimSpace(value[:i]))
+			value = strings.TrimLeft(value[i+1:], " \t")
+
+			var val string
+			if strings.HasPrefix(value, `"`) {
+				end := strings.IndexByte(value[1:], '"')
+				if end < 0 {
+					val, value = value[1:], ""
+				} else {
+					val, value = value[1:end+1], value[end+2:]
+				}
+			} else {
+				end := strings.IndexAny(value, ";,")
+				if end < 0 {
+					end = len(value)
+				}
+				val, value = strings.TrimSpace(value[:end]), value[end:]
+			}
+			l.params[key] = val
+		}
+		links = append(links, l)
+	}
+}
+
+func fetchRaw(ctx context.Context, url string) ([]byte, http.Header, error) {
+	resp, err := httpGet(ctx, url)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkError(resp); err != nil {
+		return nil, nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, resp.Header, nil
+}
+
 func (q query) All(debug ...bool) ([]*Card, error) {
-	var allCards []*Card
 	isDebug := false
 	if len(debug) == 1 {
 		isDebug = debug[0]
 	}
-	queryVals := make(url.Values)
-	for k, v := range q {
-		queryVals.Set(k, v)
+	return q.all(context.Background(), 0, isDebug, nil)
+}
+
+func (q query) AllContext(ctx context.Context) ([]*Card, error) {
+	return q.all(ctx, 0, false, nil)
+}
+
+func (q query) AllStats(debug ...bool) ([]*Card, Stats, error) {
+	isDebug := false
+	if len(debug) == 1 {
+		isDebug = debug[0]
+	}
+	var stats Stats
+	cards, err := q.all(context.Background(), 0, isDebug, &stats)
+	return cards, stats, err
+}
+
+func (q query) AllLimit(max int, debug ...bool) ([]*Card, error) {
+	isDebug := false
+	if len(debug) == 1 {
+		isDebug = debug[0]
 	}
-	nextUrl := queryUrl + "cards?" + queryVals.Encode()
+	if max <= 0 {
+		return nil, nil
+	}
+	return q.all(context.Background(), max, isDebug, nil)
+}
+
+func (q query) FirstPage(debug ...bool) ([]*Card, error) {
+	isDebug := false
+	if len(debug) == 1 {
+		isDebug = debug[0]
+	}
+	cards, _, err := fetchCards(context.Background(), q.URL(), isDebug, nil)
+	if err != nil {
+		return nil, err
+	}
+	return q.filter(cards), nil
+}
+
+// all fetches the cards of all pages. If max is greater than zero, no further pages are
+// fetched once max cards are collected. If stats is not nil, all requests are added to it.
+// The requests are aborted when ctx is done.
+func (q query) all(ctx context.Context, max int, isDebug bool, stats *Stats) ([]*Card, error) {
+	var allCards []*Card
+	nextUrl := q.URL()
 	for nextUrl != "" {
-		cards, header, err := fetchCards(nextUrl, isDebug)
+		cards, header, err := fetchCards(ctx, nextUrl, isDebug, stats)
 		if err != nil {
 			return nil, err
 		}
 
-		nextUrl = ""
-
-		if linkH, ok := header["Link"]; ok {
-			parts := strings.Split(linkH[0], ",")
-			for _, link := range parts {
-				match := linkRE.FindStringSubmatch(link)
-				if match != nil {
-					if match[2] == "next" {
-						nextUrl = match[1]
-					}
-				}
-			}
+		nextUrl = nextPage(nextUrl, parseLinks(header).Next)
+		allCards = append(allCards, q.filter(cards)...)
+		if max > 0 && len(allCards) >= max {
+			return allCards[:max], nil
 		}
-
-		allCards = append(allCards, cards...)
 	}
 	return allCards, nil
 }
 
+func (q query) URL() string {
+	queryVals := q.values()
+	if size := currentConfig().pageSize; size != DefaultPageSize {
+		queryVals.Set("pageSize", strconv.Itoa(size))
+	}
+	return cardsUrl() + "?" + queryVals.Encode()
+}
+
+func (q query) PageURL(pageNum int, pageSize int) string {
+	queryVals := q.values()
+	queryVals.Set("page", strconv.Itoa(pageNum))
+	queryVals.Set("pageSize", strconv.Itoa(pageSize))
+	return cardsUrl() + "?" + queryVals.Encode()
+}
+
+func (q query) RawAll() ([]byte, error) {
+	return q.RawAllContext(context.Background())
+}
+
+func (q query) RawAllContext(ctx context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	nextUrl := q.URL()
+	for nextUrl != "" {
+		body, header, err := fetchRaw(ctx, nextUrl)
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.Write(body)
+		nextUrl = nextPage(nextUrl, parseLinks(header).Next)
+	}
+	return buf.Bytes(), nil
+}
+
 func (q query) Page(pageNum int, debug ...bool) (cards []*Card, totalCardCount int, err error) {
-	return q.PageS(pageNum, 100, debug...)
+	return q.PageS(pageNum, currentConfig().pageSize, debug...)
 }
 
 func (q query) PageS(pageNum int, pageSize int, debug ...bool) (cards []*Card, totalCardCount int, err error) {
-	cards = nil
-	totalCardCount = 0
-	err = nil
+	isDebug := false
+	if len(debug) == 1 {
+		isDebug = debug[0]
+	}
+	res, err := q.page(context.Background(), pageNum, pageSize, isDebug)
+	if err != nil {
+		return nil, 0, err
+	}
+	return res.Cards, res.Total, nil
+}
 
-	queryVals := make(url.Values)
-	for k, v := range q {
-		queryVals.Set(k, v)
+func (q query) PlanQuery() (estimatedCards int, estimatedPages int, pageSize int, err error) {
+	pageSize = currentConfig().pageSize
+	res, err := q.page(context.Background(), 1, pageSize, false)
+	if err != nil {
+		return 0, 0, 0, err
 	}
+	return res.Total, (res.Total + pageSize - 1) / pageSize, pageSize, nil
+}
 
+func (q query) PageResult(pageNum int, pageSize int, debug ...bool) (*PageResult, error) {
 	isDebug := false
 	if len(debug) == 1 {
 		isDebug = debug[0]
 	}
+	return q.page(context.Background(), pageNum, pageSize, isDebug)
+}
 
-	queryVals.Set("page", strconv.Itoa(pageNum))
-	queryVals.Set("pageSize", strconv.Itoa(pageSize))
+func (q query) PageResultContext(ctx context.Context, pageNum int, pageSize int) (*PageResult, error) {
+	return q.page(ctx, pageNum, pageSize, false)
+}
 
-	url := queryUrl + "cards?" + queryVals.Encode()
-	cards, header, err := fetchCards(url, isDebug)
+func (q query) page(ctx context.Context, pageNum int, pageSize int, isDebug bool) (*PageResult, error) {
+	cards, header, err := fetchCards(ctx, q.PageURL(pageNum, pageSize), isDebug, nil)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+	info := pageInfo{page: pageNum, pageSize: pageSize, count: len(cards)}
+	cards = q.filter(cards)
+	res := &PageResult{
+		Cards:    cards,
+		Total:    len(cards),
+		Page:     pageNum,
+		PageSize: pageSize,
 	}
-	totalCardCount = len(cards)
 	if totals, ok := header["Total-Count"]; ok && len(totals) > 0 {
-		if totalCardCount, err = strconv.Atoi(totals[0]); err != nil {
-			return nil, 0, err
+		if res.Total, err = strconv.Atoi(totals[0]); err != nil {
+			return nil, err
 		}
+		info.total, info.hasTotal = res.Total, true
 	}
-	return cards, totalCardCount, nil
+	if _, ok := header["Link"]; ok {
+		res.HasNext = parseLinks(header).Next != ""
+		info.hasLink, info.hasNext = true, res.HasNext
+	} else {
+		res.HasNext = pageNum*pageSize < res.Total
+	}
+	validatePage(info)
+	return res, nil
 }
 
-func (q query) Random(count int, debug ...bool) ([]*Card, error) {
-	queryVals := make(url.Values)
-	for k, v := range q {
-		queryVals.Set(k, v)
+func (q query) PageLinks(pageNum int, pageSize int) (cards []*Card, links Links, err error) {
+	cards, links, err = FetchLink(q.PageURL(pageNum, pageSize))
+	return q.filter(cards), links, err
+}
+
+// FetchLink fetches the cards of a page link returned by Query.PageLinks and the links related
+// to that page.
+func FetchLink(url string) (cards []*Card, links Links, err error) {
+	cards, header, err := fetchCards(context.Background(), url, false, nil)
+	if err != nil {
+		return nil, Links{}, err
 	}
+	return cards, parseLinks(header), nil
+}
+
+// randomMaxAttempts is the number of requests in a row without a new card after which Random
+// gives up gathering more cards.
+const randomMaxAttempts = 3
 
+func (q query) Random(count int, debug ...bool) ([]*Card, error) {
 	isDebug := false
 	if len(debug) == 1 {
 		isDebug = debug[0]
 	}
+	return q.randomCards(context.Background(), count, isDebug)
+}
+
+func (q query) RandomContext(ctx context.Context, count int) ([]*Card, error) {
+	return q.randomCards(ctx, count, false)
+}
+
+func (q query) randomCards(ctx context.Context, count int, isDebug bool) ([]*Card, error) {
+	if count <= MaxPageSize {
+		return q.random(ctx, count, isDebug)
+	}
 
+	var unique []*Card
+	seen := make(map[CardId]bool)
+	for attempts := 0; len(unique) < count && attempts < randomMaxAttempts; {
+		size := count - len(unique)
+		if size > MaxPageSize {
+			size = MaxPageSize
+		}
+		cards, err := q.random(ctx, size, isDebug)
+		if err != nil {
+			return nil, err
+		}
+		attempts++
+		for _, c := range cards {
+			if len(unique) < count && !seen[c.Id] {
+				seen[c.Id] = true
+				unique = append(unique, c)
+				attempts = 0
+			}
+		}
+	}
+	return unique, nil
+}
+
+// random fetches count random cards with a single request.
+func (q query) random(ctx context.Context, count int, isDebug bool) ([]*Card, error) {
+	queryVals := q.values()
 	queryVals.Set("random", "true")
 	queryVals.Set("pageSize", strconv.Itoa(count))
 
-	url := queryUrl + "cards?" + queryVals.Encode()
-	cards, _, err := fetchCards(url, isDebug)
-	return cards, err
+	url := cardsUrl() + "?" + queryVals.Encode()
+	cards, _, err := fetchCards(ctx, url, isDebug, nil)
+	return q.filter(cards), err
 }
 
 func (q query) Copy() Query {
@@ -246,11 +855,250 @@ func (q query) Copy() Query {
 	return r
 }
 
+func (q query) Merge(other Query) Query {
+	if o, ok := other.(query); ok {
+		for k, v := range o {
+			q[k] = v
+		}
+	}
+	return q
+}
+
 func (q query) Where(column CardColumn, qry string) Query {
+	if prev, ok := q[string(column)]; ok {
+		qry = prev + "," + qry
+	}
 	q[string(column)] = qry
 	return q
 }
 
+func (q query) WhereName(name string, mode MatchMode) Query {
+	if mode == MatchExact {
+		q.whereExact("nameExact", name)
+	}
+	return q.Where(CardName, mode.value(name))
+}
+
+func (q query) WhereNameExact(name string) Query {
+	return q.WhereName(name, MatchExact)
+}
+
+func (q query) WhereArtist(artist string, mode MatchMode) Query {
+	if mode == MatchExact {
+		q.whereExact("artistExact", artist)
+	}
+	return q.Where(CardArtist, mode.value(artist))
+}
+
+func (q query) WhereArtistExact(artist string) Query {
+	return q.WhereArtist(artist, MatchExact)
+}
+
+func (q query) WhereSetName(name string, mode MatchMode) Query {
+	if mode == MatchExact {
+		q.whereExact("setNameExact", name)
+	}
+	return q.Where(CardSetName, mode.value(name))
+}
+
+func (q query) WhereSetNameContains(name string) Query {
+	return q.WhereSetName(name, MatchContains)
+}
+
+func (q query) WhereTextContains(phrase string) Query {
+	return q.Where(CardText, phrase)
+}
+
+func (q query) WhereTextAll(phrases ...string) Query {
+	if len(phrases) == 0 {
+		return q
+	}
+	return q.Where(CardText, strings.Join(phrases, ","))
+}
+
+func (q query) WhereTextAny(phrases ...string) Query {
+	if len(phrases) == 0 {
+		return q
+	}
+	return q.Where(CardText, strings.Join(phrases, "|"))
+}
+
+func (q query) WhereColorIdentityWithin(colors ...string) Query {
+	codes := ""
+	for _, c := range colors {
+		if code := parseColor(c).Code(); code != "" {
+			codes += code
+		} else {
+			// dropping the color would widen the filter, so nothing is matched instead
+			codes += unknownColorCode
+		}
+	}
+	q[clientFilterPrefix+"colorIdentityWithin"] = codes
+	return q
+}
+
+func (q query) WhereColorless() Query {
+	q[clientFilterPrefix+"colorless"] = "true"
+	return q
+}
+
+func (q query) WhereMulticolor() Query {
+	q[clientFilterPrefix+"multicolor"] = "true"
+	return q
+}
+
+func (q query) WhereHasText() Query {
+	q[clientFilterPrefix+"hasText"] = "true"
+	return q
+}
+
+func (q query) WhereExcludeTokens() Query {
+	q[clientFilterPrefix+"excludeTokens"] = "true"
+	return q
+}
+
+// whereAll filters column by all values, or does not change the query without values.
+func (q query) whereAll(column CardColumn, values []string) Query {
+	if len(values) == 0 {
+		return q
+	}
+	return q.Where(column, strings.Join(values, ","))
+}
+
+func (q query) WhereSupertype(supertypes ...Supertype) Query {
+	values := make([]string, len(supertypes))
+	for i, t := range supertypes {
+		values[i] = string(t)
+	}
+	return q.whereAll(CardSupertypes, values)
+}
+
+func (q query) WhereType(types ...Type) Query {
+	values := make([]string, len(types))
+	for i, t := range types {
+		values[i] = string(t)
+	}
+	return q.whereAll(CardTypes, values)
+}
+
+func (q query) WhereTypeAny(types ...Type) Query {
+	if len(types) == 0 {
+		return q
+	}
+	values := make([]string, len(types))
+	for i, t := range types {
+		values[i] = string(t)
+	}
+	return q.Where(CardTypes, strings.Join(values, "|"))
+}
+
+func (q query) WhereSubtype(subtypes ...Subtype) Query {
+	values := make([]string, len(subtypes))
+	for i, t := range subtypes {
+		values[i] = string(t)
+	}
+	return q.whereAll(CardSubtypes, values)
+}
+
+func (q query) WhereSet(set *Set) Query {
+	if set == nil {
+		return q
+	}
+	return q.Where(CardSet, string(set.SetCode))
+}
+
+func (q query) WhereSets(codes ...SetCode) Query {
+	var valid []string
+	for _, c := range codes {
+		if c := strings.TrimSpace(string(c)); c != "" {
+			valid = append(valid, c)
+		}
+	}
+	if len(valid) == 0 {
+		return q
+	}
+	return q.Where(CardSet, strings.Join(valid, "|"))
+}
+
+func (q query) WhereMultiverseIds(ids ...MultiverseId) Query {
+	var valid []string
+	for _, id := range ids {
+		if id != 0 {
+			valid = append(valid, strconv.FormatUint(uint64(id), 10))
+		}
+	}
+	if len(valid) == 0 {
+		return q
+	}
+	return q.Where(CardMultiverseId, strings.Join(valid, "|"))
+}
+
+func (q query) WhereRarity(rarity Rarity) Query {
+	return q.Where(CardRarity, string(rarity))
+}
+
+func (q query) WhereRarityIn(rarities ...Rarity) Query {
+	var names []string
+	for _, r := range rarities {
+		if r != "" && r != RarityUnknown {
+			names = append(names, string(r))
+		}
+	}
+	if len(names) == 0 {
+		return q
+	}
+	return q.Where(CardRarity, strings.Join(names, "|"))
+}
+
+func (q query) WhereRarityAtLeast(rarity Rarity) Query {
+	return q.WhereRarityIn(rarity.AtLeast()...)
+}
+
+func (q query) WhereLoyalty(op Comparison, loyalty int) Query {
+	return q.Where(CardLoyalty, op.value(strconv.Itoa(loyalty)))
+}
+
+func (q query) WhereLoyaltyEqual(loyalty int) Query {
+	return q.WhereLoyalty(CompareEqual, loyalty)
+}
+
+func (q query) WhereCMCEqual(cmc int) Query {
+	return q.Where(CardCMC, CompareEqual.value(strconv.Itoa(cmc)))
+}
+
+func (q query) WherePowerEqual(power int) Query {
+	return q.Where(CardPower, CompareEqual.value(strconv.Itoa(power)))
+}
+
+func (q query) WhereToughnessEqual(toughness int) Query {
+	return q.Where(CardToughness, CompareEqual.value(strconv.Itoa(toughness)))
+}
+
+func (q query) WhereRaw(key, value string) Query {
+	q[key] = value
+	return q
+}
+
+func (q query) WhereForeignName(name, language string) Query {
+	return q.Where(CardForeignName, name).Where(CardLanguage, language)
+}
+
+func (q query) WhereFormat(format string) Query {
+	return q.Where(CardGameFormat, format)
+}
+
+func (q query) WhereFormatLegality(format string, legality LegalityStatus) Query {
+	return q.Where(CardGameFormat, format).Where(CardLegality, string(legality))
+}
+
+func (q query) WhereBannedIn(format string) Query {
+	return q.WhereFormatLegality(format, LegalityBanned)
+}
+
+func (q query) WhereRestrictedIn(format string) Query {
+	return q.WhereFormatLegality(format, LegalityRestricted)
+}
+
 func (q query) OrderBy(column CardColumn) Query {
 	q["orderBy"] = string(column)
 	return q
@@ -1,7 +1,14 @@
 package mtg
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -130,3 +137,914 @@ func Test_Querys(t *testing.T) {
 		})
 	})
 }
+
+func Test_RawAll(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching the raw response of all pages", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`,
+				map[string]string{
+					"Link": `<https://api.magicthegathering.io/v1/cards?name=Bolt&page=2>; rel="last", <https://api.magicthegathering.io/v1/cards?name=Bolt&page=2>; rel="next"`,
+				}))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Chain Lightning","set":"LEG","unknownField":true,"id":"b"}]}`))
+
+		raw, err := NewQuery().Where(CardName, "Bolt").RawAll()
+		So(err, ShouldBeNil)
+
+		Convey("the bodies of all pages should be contained unmodified", func() {
+			So(string(raw), ShouldEqual, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`+"\n"+`{"cards":[{"name":"Chain Lightning","set":"LEG","unknownField":true,"id":"b"}]}`)
+		})
+
+		Convey("each page should be decodable like an API response", func() {
+			dec := json.NewDecoder(bytes.NewReader(raw))
+			var pages []json.RawMessage
+			for dec.More() {
+				var page json.RawMessage
+				So(dec.Decode(&page), ShouldBeNil)
+				pages = append(pages, page)
+			}
+			So(pages, ShouldHaveLength, 2)
+			cards, err := decodeCards(bytes.NewReader(pages[1]))
+			So(err, ShouldBeNil)
+			So(cards, ShouldContainCard, "Chain Lightning")
+		})
+
+		Convey("bodies which can not be decoded should be returned unchanged", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2",
+				httpmock.NewStringResponder(200, `{"cards":{"name":"Chain Lightning"},"unexpected":true`))
+			raw, err := NewQuery().Where(CardName, "Bolt").RawAll()
+			So(err, ShouldBeNil)
+			So(string(raw), ShouldEndWith, "\n"+`{"cards":{"name":"Chain Lightning"},"unexpected":true`)
+		})
+
+		Convey("server errors should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2",
+				httpmock.NewStringResponder(500, `{"status": "500", "error": "Internal server error"}`))
+			_, err := NewQuery().Where(CardName, "Bolt").RawAll()
+			_, isServerError := err.(ServerError)
+			So(isServerError, ShouldBeTrue)
+		})
+
+		Convey("the configured page size should be used like All", func() {
+			Configure(WithPageSize(50))
+			defer Configure(WithPageSize(DefaultPageSize))
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&pageSize=50",
+				httpmock.NewStringResponder(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`))
+
+			raw, err := NewQuery().Where(CardName, "Bolt").RawAll()
+			So(err, ShouldBeNil)
+			So(string(raw), ShouldEqual, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`)
+		})
+
+		Convey("a canceled context should abort the requests", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+				func(req *http.Request) (*http.Response, error) {
+					<-req.Context().Done()
+					return nil, req.Context().Err()
+				})
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := NewQuery().Where(CardName, "Bolt").RawAllContext(ctx)
+			So(errors.Is(err, context.Canceled), ShouldBeTrue)
+		})
+	})
+}
+
+func Test_WhereForeignName(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When searching by a foreign name", t, func() {
+		qry := NewQuery().WhereForeignName("反逆の先導者、チャンドラ", "Japanese")
+
+		Convey("both the foreign name and the language should be set", func() {
+			So(qry, ShouldResemble, NewQuery().Where(CardForeignName, "反逆の先導者、チャンドラ").Where(CardLanguage, "Japanese"))
+		})
+
+		Convey("the request should contain the encoded name and the language", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?foreignName=%E5%8F%8D%E9%80%86%E3%81%AE%E5%85%88%E5%B0%8E%E8%80%85%E3%80%81%E3%83%81%E3%83%A3%E3%83%B3%E3%83%89%E3%83%A9&language=Japanese&page=1&pageSize=100",
+				NewStringResponderWithHeader(200, `{"cards":[{"name":"Chandra, Torch of Defiance","set":"KLD","foreignNames":[{"name":"反逆の先導者、チャンドラ","language":"Japanese","multiverseid":419267}],"id":"0ef97e4324dbdcc0eaedda8f4301f68f3567d2ca"}]}`,
+					map[string]string{
+						"Total-Count": "1",
+					}))
+
+			cards, total, err := qry.Page(1)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 1)
+			So(cards, ShouldContainCard, "Chandra, Torch of Defiance")
+		})
+	})
+}
+
+func Test_WhereSameColumn(t *testing.T) {
+	Convey("When filtering the same column multiple times", t, func() {
+		qry := NewQuery().Where(CardTypes, "Creature").Where(CardTypes, "Artifact")
+
+		Convey("all values should be combined with AND", func() {
+			So(qry, ShouldResemble, query{"types": "Creature,Artifact"})
+		})
+
+		Convey("other columns should not be affected", func() {
+			qry = qry.Where(CardColors, "red|green")
+			So(qry, ShouldResemble, query{"types": "Creature,Artifact", "colors": "red|green"})
+		})
+	})
+}
+
+func Test_Merge(t *testing.T) {
+	Convey("When merging two queries", t, func() {
+		qry := NewQuery().Where(CardColors, "red").Where(CardRarity, "rare")
+		other := NewQuery().Where(CardTypes, "Creature").OrderBy(CardCMC)
+
+		Convey("the filters of both queries should be combined", func() {
+			So(qry.Merge(other), ShouldResemble, query{"colors": "red", "rarity": "rare", "types": "Creature", "orderBy": "cmc"})
+		})
+
+		Convey("the other query should not be modified", func() {
+			qry.Merge(other)
+			So(other, ShouldResemble, query{"types": "Creature", "orderBy": "cmc"})
+		})
+
+		Convey("on conflicts the value of the other query should win", func() {
+			So(qry.Merge(NewQuery().Where(CardColors, "blue")), ShouldResemble, query{"colors": "blue", "rarity": "rare"})
+		})
+
+		Convey("merging nil should make no difference", func() {
+			So(qry.Merge(nil), ShouldResemble, query{"colors": "red", "rarity": "rare"})
+		})
+	})
+}
+
+func Test_WhereName(t *testing.T) {
+	Convey("When filtering by name", t, func() {
+		Convey("MatchContains should use the plain name", func() {
+			So(NewQuery().WhereName("Bolt", MatchContains), ShouldResemble, query{"name": "Bolt"})
+		})
+		Convey("MatchExact should wrap the name in quotes", func() {
+			q := NewQuery().WhereName("Lightning Bolt", MatchExact)
+			So(q.URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?name=%22Lightning+Bolt%22")
+			So(NewQuery().WhereNameExact("Lightning Bolt"), ShouldResemble, q)
+		})
+		Convey("MatchExact should drop cards whose name only contains the name", func() {
+			cards := []*Card{{Name: "Lightning Bolt"}, {Name: "Lightning Bolt Strike"}}
+			filtered := NewQuery().WhereNameExact("lightning bolt").(query).filter(cards)
+			So(filtered, ShouldHaveLength, 1)
+			So(filtered, ShouldContainCard, "Lightning Bolt")
+			So(NewQuery().WhereName("Lightning Bolt", MatchContains).(query).filter(cards), ShouldHaveLength, 2)
+		})
+		Convey("repeated MatchExact calls should all have to match", func() {
+			cards := []*Card{{Name: "Lightning Bolt"}, {Name: "Shock"}}
+			q := NewQuery().WhereNameExact("Lightning Bolt").WhereNameExact("Shock")
+			So(q.(query).filter(cards), ShouldBeEmpty)
+			So(NewQuery().WhereNameExact("Shock").WhereNameExact("shock").(query).filter(cards), ShouldContainCard, "Shock")
+		})
+	})
+}
+
+func Test_WhereArtist(t *testing.T) {
+	Convey("When filtering by artist", t, func() {
+		Convey("MatchContains should use the plain artist", func() {
+			So(NewQuery().WhereArtist("Rush", MatchContains), ShouldResemble, query{"artist": "Rush"})
+		})
+		Convey("MatchExact should wrap the artist in quotes", func() {
+			q := NewQuery().WhereArtist("Christopher Rush", MatchExact)
+			So(q.URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?artist=%22Christopher+Rush%22")
+			So(NewQuery().WhereArtistExact("Christopher Rush"), ShouldResemble, q)
+		})
+		Convey("MatchExact should drop cards of other artists containing the name", func() {
+			cards := []*Card{{Name: "Lightning Bolt", Artist: "Christopher Rush"}, {Name: "Counterspell", Artist: "Christopher Rushmore"}}
+			filtered := NewQuery().WhereArtistExact("christopher rush").(query).filter(cards)
+			So(filtered, ShouldHaveLength, 1)
+			So(filtered, ShouldContainCard, "Lightning Bolt")
+			So(NewQuery().WhereArtist("Rush", MatchContains).(query).filter(cards), ShouldHaveLength, 2)
+		})
+		Convey("repeated MatchExact calls should all have to match", func() {
+			cards := []*Card{{Name: "Lightning Bolt", Artist: "Christopher Rush"}, {Name: "Shock", Artist: "Jon Foster"}}
+			q := NewQuery().WhereArtistExact("Christopher Rush").WhereArtistExact("Jon Foster")
+			So(q.(query).filter(cards), ShouldBeEmpty)
+		})
+	})
+}
+
+func Test_WhereSetName(t *testing.T) {
+	Convey("When filtering by set name", t, func() {
+		Convey("a partial name should use the plain name", func() {
+			So(NewQuery().WhereSetNameContains("Khans"), ShouldResemble, query{"setName": "Khans"})
+			So(NewQuery().WhereSetName("Khans", MatchContains), ShouldResemble, query{"setName": "Khans"})
+		})
+		Convey("MatchExact should wrap the name in quotes", func() {
+			q := NewQuery().WhereSetName("Khans of Tarkir", MatchExact)
+			So(q.URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?setName=%22Khans+of+Tarkir%22")
+			cards := []*Card{{Name: "Abzan Charm", SetName: "Khans of Tarkir"}, {Name: "Anafenza", SetName: "Khans of Tarkir Promos"}}
+			So(q.(query).filter(cards), ShouldHaveLength, 1)
+		})
+		Convey("repeated MatchExact calls should all have to match", func() {
+			cards := []*Card{{Name: "Abzan Charm", SetName: "Khans of Tarkir"}, {Name: "Shock", SetName: "Magic 2010"}}
+			q := NewQuery().WhereSetName("Khans of Tarkir", MatchExact).WhereSetName("Magic 2010", MatchExact)
+			So(q.(query).filter(cards), ShouldBeEmpty)
+		})
+	})
+}
+
+func Test_PageLinks(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching a page with links", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2&pageSize=1",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Chain Lightning","set":"LEG","id":"b"}]}`,
+				map[string]string{
+					"Link": `<https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=1>; rel="first", <https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=1>; rel="prev", <https://api.magicthegathering.io/v1/cards?name=Bolt&page=3&pageSize=1>; rel="last", <https://api.magicthegathering.io/v1/cards?name=Bolt&page=3&pageSize=1>; rel="next"`,
+				}))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=1",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`,
+				map[string]string{
+					"Link": `<https://api.magicthegathering.io/v1/cards?name=Bolt&page=3&pageSize=1>; rel="last", <https://api.magicthegathering.io/v1/cards?name=Bolt&page=2&pageSize=1>; rel="next"`,
+				}))
+
+		cards, links, err := NewQuery().Where(CardName, "Bolt").PageLinks(2, 1)
+		So(err, ShouldBeNil)
+		So(cards, ShouldContainCard, "Chain Lightning")
+
+		Convey("all relations should be parsed", func() {
+			So(links, ShouldResemble, Links{
+				First: "https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=1",
+				Prev:  "https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=1",
+				Next:  "https://api.magicthegathering.io/v1/cards?name=Bolt&page=3&pageSize=1",
+				Last:  "https://api.magicthegathering.io/v1/cards?name=Bolt&page=3&pageSize=1",
+			})
+		})
+
+		Convey("the previous page should be fetchable", func() {
+			cards, links, err := FetchLink(links.Prev)
+			So(err, ShouldBeNil)
+			So(cards, ShouldContainCard, "Lightning Bolt")
+			So(links.Prev, ShouldBeEmpty)
+			So(links.First, ShouldBeEmpty)
+			So(links.Next, ShouldEqual, "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2&pageSize=1")
+		})
+
+		Convey("errors should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=1",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			_, _, err := FetchLink(links.Prev)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_ParseLinks(t *testing.T) {
+	Convey("When parsing Link headers", t, func() {
+		parse := func(values ...string) Links {
+			header := make(http.Header)
+			for _, v := range values {
+				header.Add("Link", v)
+			}
+			return parseLinks(header)
+		}
+
+		Convey("the format of the API should be parsed", func() {
+			So(parse(`<https://api.magicthegathering.io/v1/cards?page=2>; rel="last", <https://api.magicthegathering.io/v1/cards?page=2>; rel="next"`), ShouldResemble, Links{
+				Next: "https://api.magicthegathering.io/v1/cards?page=2",
+				Last: "https://api.magicthegathering.io/v1/cards?page=2",
+			})
+		})
+		Convey("additional parameters should be ignored", func() {
+			So(parse(`<a>; rel="next"; title="x", <b>; type="text/html"; rel="last"`), ShouldResemble, Links{Next: "a", Last: "b"})
+		})
+		Convey("missing or additional spacing should not matter", func() {
+			So(parse(`<a>;rel="next",<b>;rel="last"`), ShouldResemble, Links{Next: "a", Last: "b"})
+			So(parse(` <a> ;  rel = "next" ,   <b>;	rel="last" `), ShouldResemble, Links{Next: "a", Last: "b"})
+		})
+		Convey("unquoted and upper case relations should be parsed", func() {
+			So(parse(`<a>; rel=next, <b>; REL=Last`), ShouldResemble, Links{Next: "a", Last: "b"})
+		})
+		Convey("a link may have multiple relations", func() {
+			So(parse(`<a>; rel="next last", <b>; rel="first prev"`), ShouldResemble, Links{First: "b", Prev: "b", Next: "a", Last: "a"})
+		})
+		Convey("commas within urls or quoted parameters should not split links", func() {
+			So(parse(`<https://example.com/cards?types=Creature,Artifact&page=2>; title="a, b"; rel="next"`), ShouldResemble, Links{
+				Next: "https://example.com/cards?types=Creature,Artifact&page=2",
+			})
+		})
+		Convey("multiple header values should be combined", func() {
+			So(parse(`<a>; rel="next"`, `<b>; rel="last"`), ShouldResemble, Links{Next: "a", Last: "b"})
+		})
+		Convey("malformed headers should be ignored", func() {
+			So(parse(`garbage`), ShouldResemble, Links{})
+			So(parse(`<a; rel="next"`), ShouldResemble, Links{})
+			So(parse(`<a>; title`), ShouldResemble, Links{})
+		})
+	})
+}
+
+func Test_WhereSet(t *testing.T) {
+	Convey("When filtering by a set", t, func() {
+		Convey("the set code should be used", func() {
+			So(NewQuery().WhereSet(&Set{SetCode: "KTK", Name: "Khans of Tarkir"}), ShouldResemble, query{"set": "KTK"})
+		})
+		Convey("a nil set should not change the query", func() {
+			So(NewQuery().Where(CardColors, "red").WhereSet(nil), ShouldResemble, query{"colors": "red"})
+		})
+	})
+}
+
+func Test_AllLimit(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching a limited number of cards", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Lightning Bolt","id":"a"},{"name":"Chain Lightning","id":"b"}]}`,
+				map[string]string{
+					"Link": `<https://api.magicthegathering.io/v1/cards?name=Bolt&page=2>; rel="next"`,
+				}))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Boltwing Marauder","id":"c"},{"name":"Bolt Bend","id":"d"}]}`,
+				map[string]string{
+					"Link": `<https://api.magicthegathering.io/v1/cards?name=Bolt&page=3>; rel="next"`,
+				}))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=3",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Bolt of Keranos","id":"e"}]}`))
+		qry := NewQuery().Where(CardName, "Bolt")
+
+		Convey("the last page should be trimmed", func() {
+			cards, err := qry.AllLimit(3)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 3)
+			So(cards[2].Name, ShouldEqual, "Boltwing Marauder")
+		})
+
+		Convey("no further pages should be fetched once the limit is reached", func() {
+			httpmock.ZeroCallCounters()
+			cards, err := qry.AllLimit(2)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 1)
+		})
+
+		Convey("if there are less cards than the limit, all should be returned", func() {
+			cards, err := qry.AllLimit(10)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 5)
+		})
+
+		Convey("a limit of zero should not fetch anything", func() {
+			httpmock.ZeroCallCounters()
+			cards, err := qry.AllLimit(0)
+			So(err, ShouldBeNil)
+			So(cards, ShouldBeEmpty)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 0)
+		})
+	})
+}
+
+func Test_RandomDeduplication(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching more random cards than fit into one page", t, func() {
+		randomCards := func(first func(call int) int, pool int) httpmock.Responder {
+			call := 0
+			return func(req *http.Request) (*http.Response, error) {
+				size, _ := strconv.Atoi(req.URL.Query().Get("pageSize"))
+				start := first(call)
+				call++
+				var cards []string
+				for id := start; id < start+size && id < pool; id++ {
+					cards = append(cards, fmt.Sprintf(`{"name":"Card %d","id":"%d"}`, id, id))
+				}
+				return httpmock.NewStringResponse(200, `{"cards":[`+strings.Join(cards, ",")+`]}`), nil
+			}
+		}
+
+		Convey("duplicates should be replaced by further requests", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards",
+				randomCards(func(call int) int { return call * 50 }, 1000))
+			httpmock.ZeroCallCounters()
+
+			cards, err := NewQuery().Random(300)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 300)
+			ids := make(map[CardId]bool)
+			for _, c := range cards {
+				ids[c.Id] = true
+			}
+			So(ids, ShouldHaveLength, 300)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 6)
+		})
+
+		Convey("the unique cards should be returned if the query matches fewer cards", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards",
+				randomCards(func(call int) int { return call * 20 }, 120))
+			httpmock.ZeroCallCounters()
+
+			cards, err := NewQuery().Random(300)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 120)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 2+randomMaxAttempts)
+		})
+
+		Convey("errors should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			_, err := NewQuery().Random(300)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_FirstPage(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching only the first page", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Lightning Bolt","id":"a","layout":"normal"},{"name":"Bolt Token","id":"b","layout":"token"}]}`,
+				map[string]string{
+					"Link": `<https://api.magicthegathering.io/v1/cards?name=Bolt&page=2>; rel="next"`,
+				}))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Boltwing Marauder","id":"c"}]}`))
+
+		Convey("the next page should not be followed", func() {
+			httpmock.ZeroCallCounters()
+			cards, err := NewQuery().Where(CardName, "Bolt").FirstPage()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 1)
+		})
+
+		Convey("client side filters should be applied", func() {
+			cards, err := NewQuery().Where(CardName, "Bolt").WhereExcludeTokens().FirstPage()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+			So(cards[0].Name, ShouldEqual, "Lightning Bolt")
+		})
+	})
+}
+
+func Test_AllStats(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching all cards with statistics", t, func() {
+		page1 := `{"cards":[{"name":"Lightning Bolt","id":"a"}]}`
+		page2 := `{"cards":[{"name":"Chain Lightning","id":"b"}]}`
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+			NewStringResponderWithHeader(200, page1,
+				map[string]string{
+					"Link": `<https://api.magicthegathering.io/v1/cards?name=Bolt&page=2>; rel="next"`,
+				}))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2",
+			httpmock.NewStringResponder(200, page2))
+
+		cards, stats, err := NewQuery().Where(CardName, "Bolt").AllStats()
+		So(err, ShouldBeNil)
+		So(cards, ShouldHaveLength, 2)
+
+		Convey("every request should be counted", func() {
+			So(stats.Requests, ShouldEqual, 2)
+		})
+		Convey("the size of all responses should be summed up", func() {
+			So(stats.Bytes, ShouldEqual, len(page1)+len(page2))
+		})
+		Convey("failed requests should be counted as well", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			_, stats, err := NewQuery().Where(CardName, "Bolt").AllStats()
+			So(err, ShouldNotBeNil)
+			So(stats.Requests, ShouldEqual, 2)
+			So(stats.Bytes, ShouldEqual, len(page1))
+		})
+	})
+}
+
+func Test_WhereRaw(t *testing.T) {
+	Convey("When setting a raw parameter", t, func() {
+		qry := NewQuery().WhereRaw("contains", "imageUrl")
+
+		Convey("it should be sent as it is", func() {
+			So(qry.(query).values().Encode(), ShouldEqual, "contains=imageUrl")
+		})
+		Convey("setting it again should replace the value", func() {
+			So(qry.WhereRaw("contains", "flavor"), ShouldResemble, query{"contains": "flavor"})
+		})
+	})
+}
+
+func Test_PageResult(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching a page result", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=2",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"},{"name":"Chain Lightning","set":"LEG","id":"b"}]}`,
+				map[string]string{
+					"Total-Count": "3",
+					"Link":        `<https://api.magicthegathering.io/v1/cards?name=Bolt&page=2&pageSize=2>; rel="last", <https://api.magicthegathering.io/v1/cards?name=Bolt&page=2&pageSize=2>; rel="next"`,
+				}))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2&pageSize=2",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Boltwing Marauder","set":"DOM","id":"c"}]}`,
+				map[string]string{
+					"Total-Count": "3",
+				}))
+
+		Convey("the first page should contain the paging information", func() {
+			res, err := NewQuery().Where(CardName, "Bolt").PageResult(1, 2)
+			So(err, ShouldBeNil)
+			So(res.Cards, ShouldHaveLength, 2)
+			So(res.Total, ShouldEqual, 3)
+			So(res.Page, ShouldEqual, 1)
+			So(res.PageSize, ShouldEqual, 2)
+			So(res.HasNext, ShouldBeTrue)
+		})
+
+		Convey("the last page should have no next page", func() {
+			res, err := NewQuery().Where(CardName, "Bolt").PageResult(2, 2)
+			So(err, ShouldBeNil)
+			So(res.Cards, ShouldContainCard, "Boltwing Marauder")
+			So(res.HasNext, ShouldBeFalse)
+		})
+
+		Convey("errors should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=2",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			res, err := NewQuery().Where(CardName, "Bolt").PageResult(1, 2)
+			So(res, ShouldBeNil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_QueryURL(t *testing.T) {
+	Convey("When building the URL of a query", t, func() {
+		qry := NewQuery().Where(CardName, "Bolt").OrderBy(CardCMC)
+
+		Convey("the URL of All should contain the filters", func() {
+			So(qry.URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?name=Bolt&orderBy=cmc")
+		})
+
+		Convey("the URL of a page should contain the page and page size", func() {
+			So(qry.PageURL(2, 10), ShouldEqual, "https://api.magicthegathering.io/v1/cards?name=Bolt&orderBy=cmc&page=2&pageSize=10")
+		})
+
+		Convey("client side filters should not be part of the URL", func() {
+			So(qry.WhereColorIdentityWithin("R").URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?name=Bolt&orderBy=cmc")
+		})
+	})
+}
+
+func Test_WhereFormat(t *testing.T) {
+	Convey("When filtering by game format", t, func() {
+		Convey("only the format should be set", func() {
+			So(NewQuery().WhereFormat("Modern").URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?gameFormat=Modern")
+		})
+
+		Convey("the legality should be set together with the format", func() {
+			So(NewQuery().WhereFormatLegality("Vintage", LegalityRestricted).URL(), ShouldEqual,
+				"https://api.magicthegathering.io/v1/cards?gameFormat=Vintage&legality=Restricted")
+		})
+
+		Convey("banned and restricted cards should set format and legality", func() {
+			So(NewQuery().WhereBannedIn("Modern"), ShouldResemble, query{"gameFormat": "Modern", "legality": "Banned"})
+			So(NewQuery().WhereRestrictedIn("Vintage"), ShouldResemble, query{"gameFormat": "Vintage", "legality": "Restricted"})
+		})
+	})
+}
+
+func Test_WhereSets(t *testing.T) {
+	Convey("When filtering by several sets", t, func() {
+		Convey("the codes should be joined with a pipe", func() {
+			So(NewQuery().WhereSets("KTK", "FRF", " ", "DTK"), ShouldResemble, query{"set": "KTK|FRF|DTK"})
+		})
+		Convey("no codes should not change the query", func() {
+			So(NewQuery().WhereSets(), ShouldResemble, query{})
+			So(NewQuery().WhereSets(""), ShouldResemble, query{})
+		})
+		Convey("the codes should be sent unchanged", func() {
+			So(NewQuery().WhereSets("pKTK", "frf"), ShouldResemble, query{"set": "pKTK|frf"})
+		})
+	})
+}
+
+func Test_EmptyResults(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When a query matches no cards", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Nothing",
+			httpmock.NewStringResponder(200, `{"cards":[]}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Nothing&page=1&pageSize=100",
+			NewStringResponderWithHeader(200, `{"cards":[]}`, map[string]string{"Total-Count": "0"}))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Nothing&pageSize=5&random=true",
+			httpmock.NewStringResponder(200, `{"cards":[]}`))
+		qry := NewQuery().Where(CardName, "Nothing")
+
+		Convey("All should return no cards and no error", func() {
+			cards, err := qry.All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldBeEmpty)
+		})
+
+		Convey("Page should return a total count of zero", func() {
+			cards, total, err := qry.Page(1)
+			So(err, ShouldBeNil)
+			So(cards, ShouldBeEmpty)
+			So(total, ShouldEqual, 0)
+		})
+
+		Convey("the page result should be empty", func() {
+			res, err := qry.PageResult(1, 100)
+			So(err, ShouldBeNil)
+			So(res.IsEmpty(), ShouldBeTrue)
+			So(res.HasNext, ShouldBeFalse)
+		})
+
+		Convey("Random should return no cards and no error", func() {
+			cards, err := qry.Random(5)
+			So(err, ShouldBeNil)
+			So(cards, ShouldBeEmpty)
+		})
+	})
+
+	Convey("When a page after the last page is fetched", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=9&pageSize=100",
+			NewStringResponderWithHeader(200, `{"cards":[]}`, map[string]string{"Total-Count": "3"}))
+
+		res, err := NewQuery().Where(CardName, "Bolt").PageResult(9, 100)
+		So(err, ShouldBeNil)
+		So(res.Cards, ShouldBeEmpty)
+		So(res.IsEmpty(), ShouldBeFalse)
+	})
+}
+
+func Test_WhereRarityIn(t *testing.T) {
+	Convey("When filtering by several rarities", t, func() {
+		Convey("the rarities should be joined with a pipe", func() {
+			So(NewQuery().WhereRarityIn(RarityRare, RarityMythicRare), ShouldResemble, query{"rarity": "Rare|Mythic Rare"})
+		})
+		Convey("the space of Mythic Rare should be encoded in the URL", func() {
+			So(NewQuery().WhereRarityIn(RarityMythicRare).URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?rarity=Mythic+Rare")
+		})
+		Convey("no rarities should not change the query", func() {
+			So(NewQuery().WhereRarityIn(), ShouldResemble, query{})
+			So(NewQuery().WhereRarityIn(RarityUnknown), ShouldResemble, query{})
+		})
+	})
+}
+
+func Test_WhereMultiverseIds(t *testing.T) {
+	Convey("When filtering by multiverse ids", t, func() {
+		Convey("the ids should be joined with a pipe", func() {
+			So(NewQuery().WhereMultiverseIds(2633, 0, 194), ShouldResemble, query{"multiverseid": "2633|194"})
+		})
+		Convey("no ids should not change the query", func() {
+			So(NewQuery().WhereMultiverseIds(), ShouldResemble, query{})
+			So(NewQuery().WhereMultiverseIds(0), ShouldResemble, query{})
+		})
+	})
+}
+
+func Test_WhereTypes(t *testing.T) {
+	Convey("When filtering by typed types", t, func() {
+		Convey("all types should be required", func() {
+			So(NewQuery().WhereType(TypeArtifact, TypeCreature), ShouldResemble, query{"types": "Artifact,Creature"})
+			So(NewQuery().WhereSupertype(SupertypeLegendary), ShouldResemble, query{"supertypes": "Legendary"})
+			So(NewQuery().WhereSubtype(SubtypeHuman, SubtypeWizard), ShouldResemble, query{"subtypes": "Human,Wizard"})
+		})
+		Convey("any of the types should be accepted", func() {
+			So(NewQuery().WhereTypeAny(TypeInstant, TypeSorcery), ShouldResemble, query{"types": "Instant|Sorcery"})
+		})
+		Convey("typed and raw filters should be combined", func() {
+			So(NewQuery().WhereType(TypeCreature).Where(CardTypes, "Artifact"), ShouldResemble, query{"types": "Creature,Artifact"})
+			So(NewQuery().WhereSubtype(Subtype("Homarid")).URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?subtypes=Homarid")
+		})
+		Convey("no types should not change the query", func() {
+			So(NewQuery().WhereType(), ShouldResemble, query{})
+			So(NewQuery().WhereTypeAny(), ShouldResemble, query{})
+			So(NewQuery().WhereSupertype(), ShouldResemble, query{})
+			So(NewQuery().WhereSubtype(), ShouldResemble, query{})
+		})
+	})
+}
+
+func Test_WhereText(t *testing.T) {
+	Convey("When filtering by oracle text", t, func() {
+		Convey("a phrase should be encoded with its spaces", func() {
+			So(NewQuery().WhereTextContains("draw a card").URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?text=draw+a+card")
+		})
+		Convey("all phrases should be joined with a comma", func() {
+			So(NewQuery().WhereTextAll("draw a card", "flying").URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?text=draw+a+card%2Cflying")
+		})
+		Convey("any phrase should be joined with a pipe", func() {
+			So(NewQuery().WhereTextAny("draw a card", "scry 1").URL(), ShouldEqual, "https://api.magicthegathering.io/v1/cards?text=draw+a+card%7Cscry+1")
+		})
+		Convey("text filters should be combined with AND", func() {
+			So(NewQuery().WhereTextAny("flying", "reach").WhereTextContains("trample"), ShouldResemble, query{"text": "flying|reach,trample"})
+		})
+		Convey("no phrases should not change the query", func() {
+			So(NewQuery().WhereTextAll(), ShouldResemble, query{})
+			So(NewQuery().WhereTextAny(), ShouldResemble, query{})
+		})
+	})
+}
+
+func Test_WhereLoyalty(t *testing.T) {
+	Convey("When filtering by loyalty", t, func() {
+		Convey("the operator should prefix the value", func() {
+			So(NewQuery().WhereLoyalty(CompareGreaterOrEqual, 4), ShouldResemble, query{"loyalty": "gte4"})
+			So(NewQuery().WhereLoyalty(CompareLess, 3), ShouldResemble, query{"loyalty": "lt3"})
+		})
+		Convey("an equal comparison should use the plain value", func() {
+			So(NewQuery().WhereLoyalty(CompareEqual, 5), ShouldResemble, query{"loyalty": "5"})
+		})
+	})
+}
+
+func Test_WhereEqual(t *testing.T) {
+	Convey("When filtering by an exact value", t, func() {
+		Convey("the plain number should be used", func() {
+			So(NewQuery().WhereCMCEqual(3), ShouldResemble, query{"cmc": "3"})
+			So(NewQuery().WherePowerEqual(2), ShouldResemble, query{"power": "2"})
+			So(NewQuery().WhereToughnessEqual(0), ShouldResemble, query{"toughness": "0"})
+			So(NewQuery().WhereLoyaltyEqual(5), ShouldResemble, query{"loyalty": "5"})
+		})
+	})
+}
+
+func Test_Copy(t *testing.T) {
+	Convey("When branching a query", t, func() {
+		base := NewQuery().Where(CardSet, "KTK")
+
+		Convey("without Copy all branches should share their filters", func() {
+			rares := base.Where(CardRarity, "Rare")
+			So(base, ShouldResemble, query{"set": "KTK", "rarity": "Rare"})
+			So(rares, ShouldResemble, base)
+		})
+
+		Convey("copies should be isolated from later changes", func() {
+			rares := base.Copy().Where(CardRarity, "Rare")
+			commons := base.Copy().Where(CardRarity, "Common").OrderBy(CardName)
+			base.Where(CardColors, "Red")
+
+			So(rares, ShouldResemble, query{"set": "KTK", "rarity": "Rare"})
+			So(commons, ShouldResemble, query{"set": "KTK", "rarity": "Common", "orderBy": "name"})
+			So(base, ShouldResemble, query{"set": "KTK", "colors": "Red"})
+		})
+
+		Convey("client side filters should be copied as well", func() {
+			within := base.Copy().WhereColorIdentityWithin("R")
+			So(within.URL(), ShouldEqual, base.URL())
+			So(base.(query).filter([]*Card{{Name: "Island", ColorIdentity: []string{"U"}}}), ShouldHaveLength, 1)
+			So(within.(query).filter([]*Card{{Name: "Island", ColorIdentity: []string{"U"}}}), ShouldBeEmpty)
+		})
+	})
+}
+
+func Test_AllLinkLoop(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When the API links a page to itself", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`,
+				map[string]string{
+					"Link": `<https://api.magicthegathering.io/v1/cards?name=Bolt>; rel="next"`,
+				}))
+
+		Convey("the fetching should stop with the cards fetched so far", func() {
+			httpmock.ZeroCallCounters()
+			cards, err := NewQuery().Where(CardName, "Bolt").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 1)
+		})
+	})
+
+	Convey("When the next link does not advance the page", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`,
+				map[string]string{
+					"Link": `<https://api.magicthegathering.io/v1/cards?name=Bolt&page=2>; rel="next"`,
+				}))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=2",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Chain Lightning","set":"LEG","id":"b"}]}`,
+				map[string]string{
+					"Link": `<https://api.magicthegathering.io/v1/cards?name=Bolt&page=1>; rel="next"`,
+				}))
+
+		Convey("the fetching should stop after the last new page", func() {
+			httpmock.ZeroCallCounters()
+			cards, err := NewQuery().Where(CardName, "Bolt").All()
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 2)
+		})
+
+		Convey("the raw response should stop as well", func() {
+			httpmock.ZeroCallCounters()
+			_, err := NewQuery().Where(CardName, "Bolt").RawAll()
+			So(err, ShouldBeNil)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 2)
+		})
+	})
+}
+
+func Test_PlanQuery(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When planning a query", t, func() {
+		Configure(WithPageSize(50))
+		defer Configure(WithPageSize(DefaultPageSize))
+
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=50",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`,
+				map[string]string{"Total-Count": "1201"}))
+
+		Convey("the total and the page count should be estimated with a single request", func() {
+			httpmock.ZeroCallCounters()
+			cards, pages, pageSize, err := NewQuery().Where(CardName, "Bolt").PlanQuery()
+			So(err, ShouldBeNil)
+			So(cards, ShouldEqual, 1201)
+			So(pages, ShouldEqual, 25)
+			So(pageSize, ShouldEqual, 50)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 1)
+		})
+
+		Convey("errors should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=50",
+				httpmock.NewStringResponder(500, `{"error":"Internal Server Error"}`))
+			_, _, _, err := NewQuery().Where(CardName, "Bolt").PlanQuery()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_InvalidQuery(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When the API rejects a parameter", t, func() {
+		Convey("the explanation of the server should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?colors=purple",
+				httpmock.NewStringResponder(400, `{"status":400,"error":"invalid color"}`))
+
+			_, err := NewQuery().Where(CardColors, "purple").All()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "invalid color")
+			So(errors.Is(err, ErrInvalidQuery), ShouldBeTrue)
+
+			se, ok := err.(ServerError)
+			So(ok, ShouldBeTrue)
+			So(se.Status, ShouldEqual, "400")
+			So(se.StatusCode, ShouldEqual, 400)
+		})
+
+		Convey("a body without a message should report the status", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?colors=purple",
+				httpmock.NewStringResponder(400, `{}`))
+
+			_, err := NewQuery().Where(CardColors, "purple").All()
+			So(errors.Is(err, ErrInvalidQuery), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "400")
+		})
+
+		Convey("a plain text body should still return a ServerError", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?colors=purple",
+				httpmock.NewStringResponder(400, `Bad Request: invalid color`))
+
+			_, err := NewQuery().Where(CardColors, "purple").All()
+			So(errors.Is(err, ErrInvalidQuery), ShouldBeTrue)
+			se, ok := err.(ServerError)
+			So(ok, ShouldBeTrue)
+			So(se.Status, ShouldEqual, "400")
+			So(se.StatusCode, ShouldEqual, 400)
+			So(se.Message, ShouldEqual, "400")
+		})
+
+		Convey("server errors built by callers should behave the same", func() {
+			se := ServerError{Status: "400", Message: "invalid color", StatusCode: 400}
+			So(errors.Is(se, ErrInvalidQuery), ShouldBeTrue)
+			So(errors.Is(ServerError{StatusCode: 404}, ErrCardNotFound), ShouldBeFalse)
+
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?colors=purple",
+				httpmock.NewStringResponder(400, `{"status":"400","error":"invalid color"}`))
+			_, err := NewQuery().Where(CardColors, "purple").All()
+			So(err == error(se), ShouldBeTrue)
+		})
+
+		Convey("other errors should not be reported as invalid queries", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?colors=purple",
+				httpmock.NewStringResponder(503, `{"status":"503","error":"Service Unavailable"}`))
+
+			_, err := NewQuery().Where(CardColors, "purple").All()
+			So(err, ShouldNotBeNil)
+			So(errors.Is(err, ErrInvalidQuery), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package mtg
+
+import "strings"
+
+// Rarity of a card as used by the API.
+type Rarity string
+
+const (
+	// RarityCommon is the rarity of common cards.
+	RarityCommon = Rarity("Common")
+	// RarityUncommon is the rarity of uncommon cards.
+	RarityUncommon = Rarity("Uncommon")
+	// RarityRare is the rarity of rare cards.
+	RarityRare = Rarity("Rare")
+	// RarityMythicRare is the rarity of mythic rare cards.
+	RarityMythicRare = Rarity("Mythic Rare")
+	// RaritySpecial is the rarity of special cards like timeshifted cards.
+	RaritySpecial = Rarity("Special")
+	// RarityBasicLand is the rarity of basic lands.
+	RarityBasicLand = Rarity("Basic Land")
+	// RarityUnknown is used for all rarities which are not known by this package.
+	RarityUnknown = Rarity("unknown")
+)
+
+// rarityTiers are the rarities of booster slots from the most to the least common. Special cards
+// and basic lands are outside of this ordering.
+var rarityTiers = []Rarity{RarityCommon, RarityUncommon, RarityRare, RarityMythicRare}
+
+// AtLeast returns r and all rarities above it, like RarityRare and RarityMythicRare for
+// RarityRare. RaritySpecial and RarityBasicLand have no place in the ordering, so only the
+// rarity itself is returned for them; RarityUnknown returns nothing.
+func (r Rarity) AtLeast() []Rarity {
+	for i, t := range rarityTiers {
+		if t == r {
+			return append([]Rarity(nil), rarityTiers[i:]...)
+		}
+	}
+	if r == RaritySpecial || r == RarityBasicLand {
+		return []Rarity{r}
+	}
+	return nil
+}
+
+var rarityNames = map[string]Rarity{
+	"common":      RarityCommon,
+	"uncommon":    RarityUncommon,
+	"rare":        RarityRare,
+	"mythic rare": RarityMythicRare,
+	"mythic":      RarityMythicRare,
+	"special":     RaritySpecial,
+	"basic land":  RarityBasicLand,
+}
+
+// RarityValue returns the rarity of the card. The rarity is matched case insensitive and "Mythic"
+// is treated as RarityMythicRare. Rarities which are not known are returned as RarityUnknown.
+func (c *Card) RarityValue() Rarity {
+	if r, ok := rarityNames[strings.ToLower(c.Rarity)]; ok {
+		return r
+	}
+	return RarityUnknown
+}
@@ -0,0 +1,57 @@
+package mtg
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Rarity(t *testing.T) {
+	Convey("When reading the rarity of a card", t, func() {
+		Convey("known rarities should be mapped", func() {
+			So((&Card{Rarity: "Common"}).RarityValue(), ShouldEqual, RarityCommon)
+			So((&Card{Rarity: "Mythic Rare"}).RarityValue(), ShouldEqual, RarityMythicRare)
+			So((&Card{Rarity: "Basic Land"}).RarityValue(), ShouldEqual, RarityBasicLand)
+		})
+
+		Convey("the rarity should be matched case insensitive", func() {
+			So((&Card{Rarity: "rare"}).RarityValue(), ShouldEqual, RarityRare)
+			So((&Card{Rarity: "mythic rare"}).RarityValue(), ShouldEqual, RarityMythicRare)
+		})
+
+		Convey("Mythic should be a mythic rare", func() {
+			So((&Card{Rarity: "Mythic"}).RarityValue(), ShouldEqual, RarityMythicRare)
+		})
+
+		Convey("unknown rarities should be mapped to RarityUnknown", func() {
+			So((&Card{Rarity: "Legendary"}).RarityValue(), ShouldEqual, RarityUnknown)
+			So((&Card{}).RarityValue(), ShouldEqual, RarityUnknown)
+		})
+	})
+
+	Convey("When filtering by rarity", t, func() {
+		Convey("the exact API value should be used", func() {
+			So(NewQuery().WhereRarity(RarityMythicRare), ShouldResemble, query{"rarity": "Mythic Rare"})
+			So(NewQuery().WhereRarity(RarityCommon), ShouldResemble, query{"rarity": "Common"})
+		})
+	})
+}
+
+func Test_RarityAtLeast(t *testing.T) {
+	Convey("When filtering by a minimum rarity", t, func() {
+		Convey("the rarity and all rarities above it should be used", func() {
+			So(RarityRare.AtLeast(), ShouldResemble, []Rarity{RarityRare, RarityMythicRare})
+			So(NewQuery().WhereRarityAtLeast(RarityRare), ShouldResemble, query{"rarity": "Rare|Mythic Rare"})
+			So(NewQuery().WhereRarityAtLeast(RarityCommon), ShouldResemble, query{"rarity": "Common|Uncommon|Rare|Mythic Rare"})
+			So(NewQuery().WhereRarityAtLeast(RarityMythicRare), ShouldResemble, query{"rarity": "Mythic Rare"})
+		})
+		Convey("special cards and basic lands should only find their own rarity", func() {
+			So(NewQuery().WhereRarityAtLeast(RaritySpecial), ShouldResemble, query{"rarity": "Special"})
+			So(NewQuery().WhereRarityAtLeast(RarityBasicLand), ShouldResemble, query{"rarity": "Basic Land"})
+		})
+		Convey("an unknown rarity should not change the query", func() {
+			So(RarityUnknown.AtLeast(), ShouldBeEmpty)
+			So(NewQuery().WhereRarityAtLeast(RarityUnknown), ShouldResemble, query{})
+		})
+	})
+}
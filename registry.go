@@ -0,0 +1,68 @@
+package mtg
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SetRegistry keeps all sets of the API in memory, so sets can be looked up by their code
+// without a request. It is safe for concurrent use. A long running service can keep the
+// registry up to date by calling Refresh periodically, for example from a time.Ticker.
+type SetRegistry interface {
+	// Get returns the set with the given code, which is matched case insensitive.
+	Get(code SetCode) (*Set, bool)
+	// Sets returns all sets of the registry, sorted by code.
+	Sets() []*Set
+	// Refresh fetches the list of all sets again. If fetching fails, the previous sets are kept.
+	Refresh() error
+}
+
+// NewSetRegistry creates a SetRegistry and loads all sets.
+func NewSetRegistry() (SetRegistry, error) {
+	r := new(setRegistry)
+	if err := r.Refresh(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+type setRegistry struct {
+	mu   sync.RWMutex
+	sets map[SetCode]*Set
+}
+
+func (r *setRegistry) Get(code SetCode) (*Set, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.sets[SetCode(strings.ToUpper(string(code)))]
+	return s, ok
+}
+
+func (r *setRegistry) Sets() []*Set {
+	r.mu.RLock()
+	sets := make([]*Set, 0, len(r.sets))
+	for _, s := range r.sets {
+		sets = append(sets, s)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(sets, func(i, j int) bool { return sets[i].SetCode < sets[j].SetCode })
+	return sets
+}
+
+func (r *setRegistry) Refresh() error {
+	sets, err := NewSetQuery().All()
+	if err != nil {
+		return err
+	}
+	byCode := make(map[SetCode]*Set, len(sets))
+	for _, s := range sets {
+		byCode[SetCode(strings.ToUpper(string(s.SetCode)))] = s
+	}
+
+	r.mu.Lock()
+	r.sets = byCode
+	r.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,87 @@
+package mtg
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_SetRegistry(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When creating a set registry", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets",
+			httpmock.NewStringResponder(200, `{"sets":[
+				{"code":"KTK","name":"Khans of Tarkir"},
+				{"code":"FRF","name":"Fate Reforged"}
+			]}`))
+		registry, err := NewSetRegistry()
+		So(err, ShouldBeNil)
+
+		Convey("sets should be found by code without further requests", func() {
+			httpmock.ZeroCallCounters()
+			set, ok := registry.Get("ktk")
+			So(ok, ShouldBeTrue)
+			So(set.Name, ShouldEqual, "Khans of Tarkir")
+
+			_, ok = registry.Get("LEA")
+			So(ok, ShouldBeFalse)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 0)
+		})
+
+		Convey("all sets should be returned sorted by code", func() {
+			sets := registry.Sets()
+			So(sets, ShouldHaveLength, 2)
+			So(sets[0].SetCode, ShouldEqual, "FRF")
+			So(sets[1].SetCode, ShouldEqual, "KTK")
+		})
+
+		Convey("refreshing should replace the sets", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets",
+				httpmock.NewStringResponder(200, `{"sets":[{"code":"DTK","name":"Dragons of Tarkir"}]}`))
+			So(registry.Refresh(), ShouldBeNil)
+			_, ok := registry.Get("KTK")
+			So(ok, ShouldBeFalse)
+			_, ok = registry.Get("DTK")
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("a failed refresh should keep the previous sets", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			So(registry.Refresh(), ShouldNotBeNil)
+			_, ok := registry.Get("KTK")
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("lookups should be safe during a refresh", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					registry.Get("KTK")
+				}()
+				go func() {
+					defer wg.Done()
+					registry.Refresh()
+				}()
+			}
+			wg.Wait()
+			_, ok := registry.Get("FRF")
+			So(ok, ShouldBeTrue)
+		})
+	})
+
+	Convey("When the sets can not be loaded", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets",
+			httpmock.NewErrorResponder(errors.New("Network Issue")))
+		registry, err := NewSetRegistry()
+		So(registry, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,152 @@
+package mtg
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultRenderWidth is the width of the text rendered by Card.Render.
+const DefaultRenderWidth = 40
+
+// minRenderWidth is the smallest width RenderWith accepts. Smaller widths are raised to it.
+const minRenderWidth = 12
+
+// RenderOptions configures Card.RenderWith.
+type RenderOptions struct {
+	// Width is the number of characters of each line, including the frame. Zero or less uses
+	// DefaultRenderWidth.
+	Width int
+	// Box draws a frame around the card with box-drawing characters.
+	Box bool
+}
+
+// Render returns the card as plain text for terminals with DefaultRenderWidth characters per
+// line: the name and mana cost, the type line, the text and the power and toughness or loyalty.
+func (c *Card) Render() string {
+	return c.RenderWith(RenderOptions{})
+}
+
+// RenderWith renders the card like Render with the given options. Long lines are wrapped at
+// spaces; words longer than a line are split.
+func (c *Card) RenderWith(opts RenderOptions) string {
+	width := opts.Width
+	if width <= 0 {
+		width = DefaultRenderWidth
+	}
+	if width < minRenderWidth {
+		width = minRenderWidth
+	}
+	inner := width
+	if opts.Box {
+		inner -= 4
+	}
+
+	var sections [][]string
+	sections = append(sections, spread(c.Name, c.ManaCost, inner))
+	if c.Type != "" {
+		sections = append(sections, wrapText(c.Type, inner))
+	}
+	var body []string
+	for _, paragraph := range strings.Split(c.Text, "\n") {
+		if strings.TrimSpace(paragraph) != "" {
+			body = append(body, wrapText(paragraph, inner)...)
+		}
+	}
+	stats := ""
+	if pt, ok := c.PT(); ok {
+		stats = pt.String()
+	} else if c.Loyalty != "" {
+		stats = "[" + c.Loyalty + "]"
+	}
+	if stats != "" {
+		body = append(body, padLeft(stats, inner))
+	}
+	if len(body) > 0 {
+		sections = append(sections, body)
+	}
+
+	var b strings.Builder
+	if !opts.Box {
+		for i, section := range sections {
+			// only the text is separated by a blank line, the name and type belong together
+			if i > 0 && len(body) > 0 && i == len(sections)-1 {
+				b.WriteString("\n")
+			}
+			for _, line := range section {
+				b.WriteString(strings.TrimRight(line, " "))
+				b.WriteString("\n")
+			}
+		}
+		return b.String()
+	}
+
+	border := strings.Repeat("─", width-2)
+	b.WriteString("┌" + border + "┐\n")
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString("├" + border + "┤\n")
+		}
+		for _, line := range section {
+			b.WriteString("│ " + padRight(line, inner) + " │\n")
+		}
+	}
+	b.WriteString("└" + border + "┘\n")
+	return b.String()
+}
+
+// spread puts left and right on one line of the given width, or on two lines if they do not
+// fit next to each other.
+func spread(left, right string, width int) []string {
+	if right == "" {
+		return wrapText(left, width)
+	}
+	gap := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap >= 1 {
+		return []string{left + strings.Repeat(" ", gap) + right}
+	}
+	return append(wrapText(left, width), padLeft(right, width))
+}
+
+// wrapText wraps s at spaces into lines of at most width characters.
+func wrapText(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for utf8.RuneCountInString(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func padLeft(s string, width int) string {
+	if n := width - utf8.RuneCountInString(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+func padRight(s string, width int) string {
+	if n := width - utf8.RuneCountInString(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
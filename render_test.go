@@ -0,0 +1,63 @@
+package mtg
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Render(t *testing.T) {
+	Convey("When rendering a card", t, func() {
+		yeti := &Card{
+			Name:      "Karplusan Yeti",
+			ManaCost:  "{3}{R}{R}",
+			Type:      "Creature — Yeti",
+			Types:     []string{"Creature"},
+			Text:      "{T}: Karplusan Yeti deals damage equal to its power to target creature. That creature deals damage equal to its power to Karplusan Yeti.",
+			Power:     "3",
+			Toughness: "3",
+		}
+
+		Convey("the plain text should contain all parts", func() {
+			So(yeti.Render(), ShouldEqual, ""+
+				"Karplusan Yeti                 {3}{R}{R}\n"+
+				"Creature — Yeti\n"+
+				"\n"+
+				"{T}: Karplusan Yeti deals damage equal\n"+
+				"to its power to target creature. That\n"+
+				"creature deals damage equal to its power\n"+
+				"to Karplusan Yeti.\n"+
+				"                                     3/3\n")
+		})
+
+		Convey("the box should have the configured width", func() {
+			out := yeti.RenderWith(RenderOptions{Width: 30, Box: true})
+			lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			So(lines[0], ShouldEqual, "┌────────────────────────────┐")
+			So(lines[1], ShouldEqual, "│ Karplusan Yeti   {3}{R}{R} │")
+			So(lines[len(lines)-2], ShouldEqual, "│                        3/3 │")
+			for _, line := range lines {
+				So(utf8.RuneCountInString(line), ShouldEqual, 30)
+			}
+		})
+
+		Convey("the loyalty of planeswalkers should be shown", func() {
+			out := (&Card{Name: "Chandra", ManaCost: "{2}{R}{R}", Type: "Planeswalker — Chandra", Loyalty: "4"}).Render()
+			So(out, ShouldEndWith, "[4]\n")
+		})
+
+		Convey("words longer than a line should be split", func() {
+			So(wrapText("a abcdefghijklmnopqrstuvwxyz b", 10), ShouldResemble, []string{
+				"a", "abcdefghij", "klmnopqrst", "uvwxyz b",
+			})
+		})
+
+		Convey("a mana cost which does not fit next to the name should get its own line", func() {
+			So(spread("Asmoranomardicadaistinaculdacar", "{5}", 20), ShouldResemble, []string{
+				"Asmoranomardicadaist", "inaculdacar", "                 {5}",
+			})
+		})
+	})
+}
@@ -0,0 +1,81 @@
+package mtg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides whether a request is made again. Use WithRetryPolicy to enable it.
+type RetryPolicy interface {
+	// ShouldRetry is called after every attempt of a request with either the response or the
+	// error of the attempt. attempt starts at 1. It returns whether the request should be made
+	// again and how long to wait before. The body of resp is closed by the caller if the
+	// request is retried.
+	ShouldRetry(resp *http.Response, err error, attempt int) (bool, time.Duration)
+}
+
+// BackoffPolicy is a RetryPolicy which retries requests which failed without a response and
+// requests answered with status 429 (Too Many Requests) or 5xx. The delay doubles with each
+// attempt, starting at BaseDelay and limited by MaxDelay. If the server sends a Retry-After
+// header (in seconds), that delay is used instead, but it is limited by MaxDelay as well.
+type BackoffPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry.
+	BaseDelay time.Duration
+	// MaxDelay limits the delay between two attempts.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy makes at most three attempts, waiting 500ms before the first retry.
+var DefaultRetryPolicy RetryPolicy = BackoffPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests. By default failed requests are
+// not retried; use WithRetryPolicy(DefaultRetryPolicy) for sensible retries or nil to disable them.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *config) {
+		c.retry = policy
+	}
+}
+
+// ShouldRetry implements the RetryPolicy interface.
+func (p BackoffPolicy) ShouldRetry(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= p.MaxAttempts {
+		return false, 0
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) {
+			return false, 0
+		}
+		return true, p.delay(attempt)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return false, 0
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+		d := time.Duration(secs) * time.Second
+		if p.MaxDelay > 0 && d > p.MaxDelay {
+			d = p.MaxDelay
+		}
+		return true, d
+	}
+	return true, p.delay(attempt)
+}
+
+func (p BackoffPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
@@ -0,0 +1,121 @@
+package mtg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type countingPolicy struct {
+	calls int
+}
+
+func (p *countingPolicy) ShouldRetry(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	p.calls++
+	return BackoffPolicy{MaxAttempts: 3}.ShouldRetry(resp, err, attempt)
+}
+
+func Test_BackoffPolicy(t *testing.T) {
+	Convey("With the backoff policy", t, func() {
+		p := BackoffPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+		status := func(code int) *http.Response {
+			return &http.Response{StatusCode: code, Header: make(http.Header)}
+		}
+
+		Convey("successful and client error responses should not be retried", func() {
+			retry, _ := p.ShouldRetry(status(200), nil, 1)
+			So(retry, ShouldBeFalse)
+			retry, _ = p.ShouldRetry(status(404), nil, 1)
+			So(retry, ShouldBeFalse)
+		})
+
+		Convey("429, 5xx and network errors should be retried", func() {
+			retry, _ := p.ShouldRetry(status(429), nil, 1)
+			So(retry, ShouldBeTrue)
+			retry, _ = p.ShouldRetry(status(503), nil, 1)
+			So(retry, ShouldBeTrue)
+			retry, _ = p.ShouldRetry(nil, errors.New("Network Issue"), 1)
+			So(retry, ShouldBeTrue)
+		})
+
+		Convey("canceled requests should not be retried", func() {
+			retry, _ := p.ShouldRetry(nil, context.Canceled, 1)
+			So(retry, ShouldBeFalse)
+			retry, _ = p.ShouldRetry(nil, ErrCircuitOpen, 1)
+			So(retry, ShouldBeFalse)
+		})
+
+		Convey("the delay should double up to the maximum", func() {
+			_, d := p.ShouldRetry(status(500), nil, 1)
+			So(d, ShouldEqual, time.Second)
+			_, d = p.ShouldRetry(status(500), nil, 2)
+			So(d, ShouldEqual, 2*time.Second)
+			_, d = p.ShouldRetry(status(500), nil, 3)
+			So(d, ShouldEqual, 3*time.Second)
+		})
+
+		Convey("Retry-After should be respected", func() {
+			resp := status(429)
+			resp.Header.Set("Retry-After", "2")
+			_, d := p.ShouldRetry(resp, nil, 3)
+			So(d, ShouldEqual, 2*time.Second)
+		})
+
+		Convey("Retry-After should be limited by MaxDelay", func() {
+			resp := status(429)
+			resp.Header.Set("Retry-After", "3600")
+			_, d := p.ShouldRetry(resp, nil, 1)
+			So(d, ShouldEqual, 3*time.Second)
+		})
+
+		Convey("there should be no more than MaxAttempts attempts", func() {
+			retry, _ := p.ShouldRetry(status(500), nil, 4)
+			So(retry, ShouldBeFalse)
+		})
+	})
+}
+
+func Test_RetryPolicy(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("With a retry policy", t, func() {
+		policy := &countingPolicy{}
+		Configure(WithRetryPolicy(policy))
+		defer Configure(WithRetryPolicy(nil))
+
+		Convey("temporary errors should be retried", func() {
+			calls := 0
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/types",
+				func(req *http.Request) (*http.Response, error) {
+					calls++
+					if calls == 1 {
+						return httpmock.NewStringResponse(503, `{"status": "503", "error":"Service unavailable"}`), nil
+					}
+					return httpmock.NewStringResponse(200, `{"types":["Artifact"]}`), nil
+				})
+
+			types, err := GetTypes()
+			So(err, ShouldBeNil)
+			So(types, ShouldResemble, []string{"Artifact"})
+			So(calls, ShouldEqual, 2)
+			So(policy.calls, ShouldEqual, 2)
+		})
+
+		Convey("the last error should be returned if all attempts fail", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/types",
+				httpmock.NewStringResponder(500, `{"status": "500", "error":"Internal server error"}`))
+			httpmock.ZeroCallCounters()
+
+			_, err := GetTypes()
+			_, isServerError := err.(ServerError)
+			So(isServerError, ShouldBeTrue)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 3)
+		})
+	})
+}
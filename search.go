@@ -0,0 +1,82 @@
+package mtg
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// SearchOptions describes a simple card search, see Search. Empty fields do not filter.
+type SearchOptions struct {
+	// Name finds all cards whose name contains the value.
+	Name string
+	// Colors finds the cards which have all of the colors.
+	Colors []string
+	// Types finds the cards which have all of the types, like "Artifact" and "Creature".
+	Types []string
+	// CMCMin finds the cards with a converted mana cost of at least the value.
+	CMCMin int
+	// CMCMax finds the cards with a converted mana cost of at most the value. Zero does not
+	// filter; use a Query with WhereRaw(string(CardCMC), "0") to find cards costing nothing.
+	CMCMax int
+	// Rarity finds the cards of the rarity.
+	Rarity Rarity
+	// Set finds the cards of the set.
+	Set SetCode
+	// Format finds the cards which are legal in the game format, like "Modern".
+	Format string
+}
+
+// Query builds the Query for the options. An error is returned if the options do not filter at
+// all, which would fetch every card of the API, or if they contradict each other.
+func (o SearchOptions) Query() (Query, error) {
+	if o.CMCMin < 0 || o.CMCMax < 0 {
+		return nil, errors.New("the converted mana cost can not be negative")
+	}
+	if o.CMCMax > 0 && o.CMCMin > o.CMCMax {
+		return nil, errors.New("the minimum converted mana cost is greater than the maximum")
+	}
+
+	q := NewQuery()
+	empty := true
+	where := func(column CardColumn, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			q.Where(column, value)
+			empty = false
+		}
+	}
+	where(CardName, o.Name)
+	where(CardColors, strings.Join(o.Colors, ","))
+	where(CardTypes, strings.Join(o.Types, ","))
+	switch {
+	case o.CMCMin > 0 && o.CMCMin == o.CMCMax:
+		where(CardCMC, strconv.Itoa(o.CMCMin))
+	default:
+		if o.CMCMin > 0 {
+			where(CardCMC, CompareGreaterOrEqual.value(strconv.Itoa(o.CMCMin)))
+		}
+		if o.CMCMax > 0 {
+			where(CardCMC, CompareLessOrEqual.value(strconv.Itoa(o.CMCMax)))
+		}
+	}
+	if o.Rarity != "" && o.Rarity != RarityUnknown {
+		where(CardRarity, string(o.Rarity))
+	}
+	where(CardSet, string(o.Set))
+	where(CardGameFormat, o.Format)
+
+	if empty {
+		return nil, errors.New("the search options do not filter any cards")
+	}
+	return q, nil
+}
+
+// Search fetches all cards matching the options. It is a shortcut for building the Query with
+// SearchOptions.Query and calling All; use the Query directly for paging or further filters.
+func Search(opts SearchOptions) ([]*Card, error) {
+	q, err := opts.Query()
+	if err != nil {
+		return nil, err
+	}
+	return q.All()
+}
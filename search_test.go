@@ -0,0 +1,66 @@
+package mtg
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Search(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When searching with options", t, func() {
+		Convey("all options should be added to the query", func() {
+			q, err := SearchOptions{
+				Name:   "Dragon",
+				Colors: []string{"Red", "Black"},
+				Types:  []string{"Creature"},
+				CMCMin: 4,
+				CMCMax: 6,
+				Rarity: RarityMythicRare,
+				Set:    "KTK",
+				Format: "Modern",
+			}.Query()
+			So(err, ShouldBeNil)
+			So(q, ShouldResemble, query{
+				"name":       "Dragon",
+				"colors":     "Red,Black",
+				"types":      "Creature",
+				"cmc":        "gte4,lte6",
+				"rarity":     "Mythic Rare",
+				"set":        "KTK",
+				"gameFormat": "Modern",
+			})
+		})
+
+		Convey("an equal minimum and maximum should search the exact cost", func() {
+			q, err := SearchOptions{CMCMin: 3, CMCMax: 3}.Query()
+			So(err, ShouldBeNil)
+			So(q, ShouldResemble, query{"cmc": "3"})
+		})
+
+		Convey("empty options should return an error", func() {
+			_, err := SearchOptions{Name: " ", Rarity: RarityUnknown}.Query()
+			So(err, ShouldNotBeNil)
+			_, err = Search(SearchOptions{})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("conflicting costs should return an error", func() {
+			_, err := SearchOptions{CMCMin: 5, CMCMax: 2}.Query()
+			So(err, ShouldNotBeNil)
+			_, err = SearchOptions{CMCMin: -1}.Query()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("the cards should be fetched", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&set=LEA",
+				httpmock.NewStringResponder(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"}]}`))
+			cards, err := Search(SearchOptions{Name: "Bolt", Set: "LEA"})
+			So(err, ShouldBeNil)
+			So(cards, ShouldContainCard, "Lightning Bolt")
+		})
+	})
+}
@@ -1,12 +1,18 @@
 package mtg
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type setColumn string
@@ -44,6 +50,9 @@ type Set struct {
 	// The type of border on the cards, either “white”, “black” or “silver”
 	Border string `json:"border"`
 	// Type of set. One of: “core”, “expansion”, “reprint”, “box”, “un”, “from the vault”, “premium deck”, “duel deck”, “starter”, “commander”, “planechase”, “archenemy”, “promo”, “vanguard”, “masters”
+	// Use SetType to get it as typed value.
+	Type string `json:"type"`
+	// Type of set as named by older versions of the API. Use SetType, which falls back to it.
 	Expansion string `json:"expansion"`
 	// Present and set to true if the set was only released online
 	OnlineOnly bool `json:"onlineOnly"`
@@ -60,22 +69,230 @@ type SetQuery interface {
 	Copy() SetQuery
 	// All returns alls Sets which match the query
 	All() ([]*Set, error)
+	// AllContext returns all Sets which match the query like All. The requests are aborted when
+	// ctx is done.
+	AllContext(ctx context.Context) ([]*Set, error)
 	// Page returns the Sets of the given page and the total count of sets which match the query.
 	// The default PageSize is 500. See also PageS
 	Page(pageNum int) (sets []*Set, totalSetCount int, err error)
 	// PageS returns the Sets of the given page and page size. It also returns the total count of sets
 	// which match the query.
 	PageS(pageNum int, pageSize int) (sets []*Set, totalSetCount int, err error)
+	// PageSContext works like PageS. The request is aborted when ctx is done.
+	PageSContext(ctx context.Context, pageNum int, pageSize int) (sets []*Set, totalSetCount int, err error)
+
+	// URL returns the URL of the first request All would make, without making it.
+	URL() string
+	// PageURL returns the URL PageS would fetch for the given page, without fetching it.
+	PageURL(pageNum int, pageSize int) string
 }
 
 type setQuery map[string]string
 
 // GenerateBooster returns a slice of cards which contains cards like a booster of the given set.
+//
+// The booster endpoint of the API has no parameters, so every booster follows the default
+// Booster definition of the set. To build variant boosters (like for a cube or a sealed event
+// with other slots), change the Booster definition of the set and use BuildBooster with the
+// cards of the set.
 func (sc SetCode) GenerateBooster() ([]*Card, error) {
-	cards, _, err := fetchCards(fmt.Sprintf("%ssets/%s/booster", queryUrl, sc), false)
+	return sc.GenerateBoosterContext(context.Background())
+}
+
+// GenerateBoosterContext works like GenerateBooster. The request is aborted when ctx is done.
+func (sc SetCode) GenerateBoosterContext(ctx context.Context) ([]*Card, error) {
+	cards, _, err := fetchCards(ctx, fmt.Sprintf("%s/%s/booster", setsUrl(), sc), false, nil)
 	return cards, err
 }
 
+// BoosterBatch configures SetCode.GenerateBoosters.
+type BoosterBatch struct {
+	// Count is the number of boosters to generate.
+	Count int
+	// Concurrency is the maximum number of boosters which are generated at the same time. Values
+	// below 1 generate one booster after the other.
+	Concurrency int
+	// Partial makes GenerateBoosters return the boosters generated so far together with the
+	// error. By default no boosters are returned if the batch fails or is canceled.
+	Partial bool
+}
+
+// GenerateBoosters generates batch.Count boosters of the set, for example the packs of a draft.
+// If generating a booster fails, the remaining boosters are not generated and the first error
+// is returned; if ctx is done, the error of ctx is returned. With batch.Partial the boosters are
+// returned even then, with nil entries for the boosters which were not generated. A count of
+// zero generates nothing, a negative count is an error.
+func (sc SetCode) GenerateBoosters(ctx context.Context, batch BoosterBatch) ([][]*Card, error) {
+	if batch.Count < 0 {
+		return nil, fmt.Errorf("invalid booster count %d", batch.Count)
+	}
+	if batch.Count == 0 {
+		return nil, nil
+	}
+	concurrency := batch.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	parent := ctx
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	boosters := make([][]*Card, batch.Count)
+	var (
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	jobs := make(chan int)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				booster, err := sc.GenerateBoosterContext(ctx)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				boosters[i] = booster
+			}
+		}()
+	}
+	sent := 0
+	for sent < batch.Count && ctx.Err() == nil {
+		select {
+		case jobs <- sent:
+			sent++
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	err := firstErr
+	if parentErr := parent.Err(); parentErr != nil && (err != nil || sent < batch.Count) {
+		// the requests failed because the caller canceled them
+		err = parentErr
+	}
+	if err != nil {
+		if batch.Partial {
+			return boosters, err
+		}
+		return nil, err
+	}
+	return boosters, nil
+}
+
+// RandomCard returns a random card of the set. The error wraps ErrCardNotFound if the set has
+// no cards.
+func (sc SetCode) RandomCard() (*Card, error) {
+	cards, err := NewQuery().Where(CardSet, string(sc)).Random(1)
+	if err != nil {
+		return nil, err
+	}
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("%w: no card in set %s", ErrCardNotFound, sc)
+	}
+	return cards[0], nil
+}
+
+// Cards returns all cards of the set.
+func (sc SetCode) Cards() ([]*Card, error) {
+	return NewQuery().Where(CardSet, string(sc)).All()
+}
+
+// FetchWithCards fetches the set and all of its cards. Both are requested concurrently. If either
+// fails, the error names what failed and no set or cards are returned.
+func (sc SetCode) FetchWithCards() (*Set, []*Card, error) {
+	return sc.FetchWithCardsContext(context.Background())
+}
+
+// FetchWithCardsContext works like FetchWithCards. The requests are aborted when ctx is done.
+func (sc SetCode) FetchWithCardsContext(ctx context.Context) (*Set, []*Card, error) {
+	var (
+		set      *Set
+		cards    []*Card
+		setErr   error
+		cardsErr error
+		wg       sync.WaitGroup
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		set, setErr = sc.FetchContext(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		cards, cardsErr = NewQuery().Where(CardSet, string(sc)).(query).all(ctx, 0, false, nil)
+	}()
+	wg.Wait()
+
+	switch {
+	case setErr != nil && cardsErr != nil:
+		return nil, nil, fmt.Errorf("failed to fetch set %s: %w; failed to fetch its cards: %v", sc, setErr, cardsErr)
+	case setErr != nil:
+		return nil, nil, fmt.Errorf("failed to fetch set %s: %w", sc, setErr)
+	case cardsErr != nil:
+		return nil, nil, fmt.Errorf("failed to fetch the cards of set %s: %w", sc, cardsErr)
+	}
+	return set, cards, nil
+}
+
+// Completion compares a collection with the cards of the set. owned contains the cards of the
+// collection, each given either by its Id or by its collector number in this set.
+//
+// Cards are counted by their collector number without the letter suffix, so the faces of
+// double-faced, split and flip cards ("12a" and "12b") count as a single card and owning either
+// face (or the number without suffix) completes it. Cards without a number are counted by Id.
+// The API does not distinguish foil and nonfoil printings, so foils are owned like any other
+// copy. missing contains one card for every card which is not owned, in the order of the set.
+func (sc SetCode) Completion(owned []string) (have, total int, missing []*Card, err error) {
+	cards, err := sc.Cards()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	ownedSet := make(map[string]bool, len(owned))
+	for _, o := range owned {
+		ownedSet[strings.TrimSpace(o)] = true
+	}
+
+	keys := make(map[string]bool)
+	var order []string
+	first := make(map[string]*Card)
+	for _, c := range cards {
+		key := completionKey(c)
+		if _, ok := keys[key]; !ok {
+			keys[key] = false
+			order = append(order, key)
+			first[key] = c
+		}
+		if ownedSet[string(c.Id)] || (c.Number != "" && (ownedSet[c.Number] || ownedSet[key])) {
+			keys[key] = true
+		}
+	}
+
+	for _, key := range order {
+		if keys[key] {
+			have++
+		} else {
+			missing = append(missing, first[key])
+		}
+	}
+	return have, len(order), missing, nil
+}
+
+// completionKey returns the key a card is counted by in SetCode.Completion.
+func completionKey(c *Card) string {
+	if c.Number == "" {
+		return "id:" + string(c.Id)
+	}
+	return strings.TrimRightFunc(c.Number, func(r rune) bool { return r >= 'a' && r <= 'z' })
+}
+
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (bc *BoosterContent) UnmarshalJSON(data []byte) error {
 	var s string
@@ -107,6 +324,19 @@ func (s *Set) String() string {
 	return fmt.Sprintf("%s (%s)", s.Name, s.SetCode)
 }
 
+// Contains returns true if the card belongs to the set. The set codes are compared case insensitive.
+func (s *Set) Contains(card *Card) bool {
+	if card == nil {
+		return false
+	}
+	return strings.EqualFold(string(card.Set), string(s.SetCode))
+}
+
+// Released returns the parsed ReleaseDate of the set.
+func (s *Set) Released() (time.Time, error) {
+	return parseDate(s.ReleaseDate)
+}
+
 // NewSetQuery returns a new SetQuery
 func NewSetQuery() SetQuery {
 	return make(setQuery)
@@ -114,18 +344,109 @@ func NewSetQuery() SetQuery {
 
 // Fetch returns the Set of the given SetCode.
 func (sc SetCode) Fetch() (*Set, error) {
-	sets, _, err := fetchSets(fmt.Sprintf("%ssets/%s", queryUrl, sc))
+	return sc.FetchContext(context.Background())
+}
+
+// FetchContext returns the Set of the given SetCode. The request is aborted when ctx is done.
+func (sc SetCode) FetchContext(ctx context.Context) (*Set, error) {
+	url := fmt.Sprintf("%s/%s", setsUrl(), sc)
+	cache, read := cacheFor(ctx)
+	if cache != nil && read {
+		if data, ok := cache.Get(url); ok {
+			if sets, err := decodeSets(bytes.NewReader(data)); err == nil && len(sets) == 1 {
+				return sets[0], nil
+			}
+		}
+	}
+
+	resp, err := httpGet(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkError(resp); err != nil {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, notFound(err, ErrSetNotFound)
+		}
+		return nil, err
+	}
+	var body io.Reader = resp.Body
+	var data bytes.Buffer
+	if cache != nil {
+		body = io.TeeReader(resp.Body, &data)
+	}
+	sets, err := decodeSets(body)
 	if err != nil {
 		return nil, err
 	}
 	if len(sets) != 1 {
-		return nil, fmt.Errorf("Set %q not found", string(sc))
+		return nil, fmt.Errorf("%w: no set with code %s", ErrSetNotFound, sc)
+	}
+	if cache != nil {
+		cache.Set(url, data.Bytes())
 	}
 	return sets[0], nil
 }
 
-func fetchSets(url string) ([]*Set, http.Header, error) {
-	resp, err := http.Get(url)
+// Exists reports whether there is a set with the SetCode. The response is not decoded, which is
+// cheaper than Fetch.
+func (sc SetCode) Exists() (bool, error) {
+	return exists(context.Background(), fmt.Sprintf("%s/%s", setsUrl(), sc))
+}
+
+// SetErrors contains the errors of FetchSets by the code of the set which could not be fetched.
+type SetErrors map[SetCode]error
+
+// Error implements the error interface
+func (se SetErrors) Error() string {
+	codes := make([]string, 0, len(se))
+	for sc := range se {
+		codes = append(codes, string(sc))
+	}
+	sort.Strings(codes)
+	msgs := make([]string, len(codes))
+	for i, c := range codes {
+		msgs[i] = fmt.Sprintf("%s: %v", c, se[SetCode(c)])
+	}
+	return "failed to fetch sets: " + strings.Join(msgs, ", ")
+}
+
+// FetchSets fetches the sets of all given codes concurrently. The sets are returned in the order
+// of the codes. If some sets could not be fetched, their entries are nil and the returned error
+// is a SetErrors containing the error for each of those codes.
+func FetchSets(codes ...SetCode) ([]*Set, error) {
+	return FetchSetsContext(context.Background(), codes...)
+}
+
+// FetchSetsContext works like FetchSets. The requests are aborted when ctx is done.
+func FetchSetsContext(ctx context.Context, codes ...SetCode) ([]*Set, error) {
+	sets := make([]*Set, len(codes))
+	errs := make([]error, len(codes))
+
+	var wg sync.WaitGroup
+	for i, sc := range codes {
+		wg.Add(1)
+		go func(i int, sc SetCode) {
+			defer wg.Done()
+			sets[i], errs[i] = sc.FetchContext(ctx)
+		}(i, sc)
+	}
+	wg.Wait()
+
+	setErrs := make(SetErrors)
+	for i, err := range errs {
+		if err != nil {
+			setErrs[codes[i]] = err
+		}
+	}
+	if len(setErrs) > 0 {
+		return sets, setErrs
+	}
+	return sets, nil
+}
+
+func fetchSets(ctx context.Context, url string) ([]*Set, http.Header, error) {
+	resp, err := httpGet(ctx, url)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -133,51 +454,47 @@ func fetchSets(url string) ([]*Set, http.Header, error) {
 	if err := checkError(resp); err != nil {
 		return nil, nil, err
 	}
+	sets, err := decodeSets(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sets, resp.Header, nil
+}
 
+// decodeSets decodes a response of the sets endpoints, which contains either a list of sets or a
+// single set.
+func decodeSets(r io.Reader) ([]*Set, error) {
 	sr := new(struct {
 		Sets []*Set `json:"sets"`
 		Set  *Set   `json:"set"`
 	})
-	decoder := json.NewDecoder(resp.Body)
-	err = decoder.Decode(&sr)
-	if err != nil {
-		return nil, nil, err
+	decoder := json.NewDecoder(r)
+	if err := decoder.Decode(&sr); err != nil {
+		return nil, err
 	}
 	if sr.Set != nil {
-		return []*Set{sr.Set}, resp.Header, nil
+		return []*Set{sr.Set}, nil
 	}
-	return sr.Sets, resp.Header, nil
+	return sr.Sets, nil
 }
 
 // All returns alls Sets which match the query
 func (q setQuery) All() ([]*Set, error) {
+	return q.AllContext(context.Background())
+}
+
+// AllContext returns all Sets which match the query. The requests are aborted when ctx is done.
+func (q setQuery) AllContext(ctx context.Context) ([]*Set, error) {
 	var allSets []*Set
 
-	queryVals := make(url.Values)
-	for k, v := range q {
-		queryVals.Set(k, v)
-	}
-	nextUrl := queryUrl + "sets?" + queryVals.Encode()
+	nextUrl := q.URL()
 	for nextUrl != "" {
-		sets, header, err := fetchSets(nextUrl)
+		sets, header, err := fetchSets(ctx, nextUrl)
 		if err != nil {
 			return nil, err
 		}
 
-		nextUrl = ""
-
-		if linkH, ok := header["Link"]; ok {
-			parts := strings.Split(linkH[0], ",")
-			for _, link := range parts {
-				match := linkRE.FindStringSubmatch(link)
-				if match != nil {
-					if match[2] == "next" {
-						nextUrl = match[1]
-					}
-				}
-			}
-		}
-
+		nextUrl = nextPage(nextUrl, parseLinks(header).Next)
 		allSets = append(allSets, sets...)
 	}
 	return allSets, nil
@@ -192,20 +509,12 @@ func (q setQuery) Page(pageNum int) (sets []*Set, totalSetCount int, err error)
 // PageS returns the Sets of the given page and page size. It also returns the total count of sets
 // which match the query.
 func (q setQuery) PageS(pageNum int, pageSize int) (sets []*Set, totalSetCount int, err error) {
-	sets = nil
-	totalSetCount = 0
-	err = nil
-
-	queryVals := make(url.Values)
-	for k, v := range q {
-		queryVals.Set(k, v)
-	}
-
-	queryVals.Set("page", strconv.Itoa(pageNum))
-	queryVals.Set("pageSize", strconv.Itoa(pageSize))
+	return q.PageSContext(context.Background(), pageNum, pageSize)
+}
 
-	url := queryUrl + "sets?" + queryVals.Encode()
-	sets, header, err := fetchSets(url)
+// PageSContext works like PageS. The request is aborted when ctx is done.
+func (q setQuery) PageSContext(ctx context.Context, pageNum int, pageSize int) (sets []*Set, totalSetCount int, err error) {
+	sets, header, err := fetchSets(ctx, q.PageURL(pageNum, pageSize))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -218,6 +527,27 @@ func (q setQuery) PageS(pageNum int, pageSize int) (sets []*Set, totalSetCount i
 	return sets, totalSetCount, nil
 }
 
+// URL returns the URL of the first request All would make.
+func (q setQuery) URL() string {
+	return setsUrl() + "?" + q.values().Encode()
+}
+
+// PageURL returns the URL PageS would fetch for the given page.
+func (q setQuery) PageURL(pageNum int, pageSize int) string {
+	queryVals := q.values()
+	queryVals.Set("page", strconv.Itoa(pageNum))
+	queryVals.Set("pageSize", strconv.Itoa(pageSize))
+	return setsUrl() + "?" + queryVals.Encode()
+}
+
+func (q setQuery) values() url.Values {
+	queryVals := make(url.Values)
+	for k, v := range q {
+		queryVals.Set(k, v)
+	}
+	return queryVals
+}
+
 // Copy creates a copy of the SetQuery.
 func (q setQuery) Copy() SetQuery {
 	r := make(setQuery)
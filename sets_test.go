@@ -1,7 +1,10 @@
 package mtg
 
 import (
+	"context"
 	"errors"
+	"net/http"
+	"sync/atomic"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -116,6 +119,20 @@ func Test_SetQuery(t *testing.T) {
 			Convey("fetching an invalid setcode should return an error", func() {
 				_, err := SetCode("FOO_BAR").Fetch()
 				So(err, ShouldNotBeNil)
+				So(errors.Is(err, ErrSetNotFound), ShouldBeTrue)
+			})
+			Convey("an unknown setcode should be reported as a missing set, not a missing card", func() {
+				httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/UNKNOWN",
+					httpmock.NewStringResponder(404, `{"status":"404","error":"Not Found"}`))
+				_, err := SetCode("UNKNOWN").Fetch()
+				So(errors.Is(err, ErrSetNotFound), ShouldBeTrue)
+				So(errors.Is(err, ErrCardNotFound), ShouldBeFalse)
+				_, isServerError := err.(ServerError)
+				So(isServerError, ShouldBeTrue)
+
+				_, err = (&Card{Name: "Unknown", Set: "UNKNOWN"}).FetchSet()
+				So(errors.Is(err, ErrSetNotFound), ShouldBeTrue)
+				So(errors.Is(err, ErrCardNotFound), ShouldBeFalse)
 			})
 			Convey("when we have network issues, there should also be an error", func() {
 				_, err := SetCode("network_issue").Fetch()
@@ -158,3 +175,319 @@ func Test_SetQuery(t *testing.T) {
 		})
 	})
 }
+
+func Test_SetContains(t *testing.T) {
+	Convey("When checking if a set contains a card", t, func() {
+		set := &Set{SetCode: "KTK", Name: "Khans of Tarkir"}
+
+		Convey("cards with the same set code should be contained", func() {
+			So(set.Contains(&Card{Name: "Mardu Heart-Piercer", Set: "KTK"}), ShouldBeTrue)
+		})
+		Convey("the set code should be compared case insensitive", func() {
+			So(set.Contains(&Card{Name: "Mardu Heart-Piercer", Set: "ktk"}), ShouldBeTrue)
+		})
+		Convey("cards of other sets should not be contained", func() {
+			So(set.Contains(&Card{Name: "Lightning Bolt", Set: "M10"}), ShouldBeFalse)
+		})
+		Convey("only the set code should be compared, not the set name", func() {
+			So(set.Contains(&Card{Name: "Mardu Heart-Piercer", Set: "Khans of Tarkir", SetName: "Khans of Tarkir"}), ShouldBeFalse)
+			So(set.Contains(&Card{Name: "Mardu Heart-Piercer", Set: "KTK", SetName: "Khans"}), ShouldBeTrue)
+		})
+		Convey("nil cards should not be contained", func() {
+			So(set.Contains(nil), ShouldBeFalse)
+		})
+	})
+}
+
+func Test_FetchSets(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching multiple sets", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK",
+			httpmock.NewStringResponder(200, `{"set":{"code":"KTK","name":"Khans of Tarkir","type":"expansion","border":"black","releaseDate":"2014-09-26","block":"Khans of Tarkir"}}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/FRF",
+			httpmock.NewStringResponder(200, `{"set":{"code":"FRF","name":"Fate Reforged","type":"expansion","border":"black","releaseDate":"2015-01-23","block":"Khans of Tarkir"}}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/DTK",
+			httpmock.NewStringResponder(200, `{"set":{"code":"DTK","name":"Dragons of Tarkir","type":"expansion","border":"black","releaseDate":"2015-03-27","block":"Khans of Tarkir"}}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/network_issue",
+			httpmock.NewErrorResponder(errors.New("Network Issue")))
+
+		Convey("the sets should be returned in the given order", func() {
+			sets, err := FetchSets("DTK", "KTK", "FRF")
+			So(err, ShouldBeNil)
+			So(sets, ShouldHaveLength, 3)
+			So(sets[0].Name, ShouldEqual, "Dragons of Tarkir")
+			So(sets[1].Name, ShouldEqual, "Khans of Tarkir")
+			So(sets[2].Name, ShouldEqual, "Fate Reforged")
+		})
+
+		Convey("errors should be reported per set code", func() {
+			sets, err := FetchSets("KTK", "network_issue", "FRF")
+			So(err, ShouldNotBeNil)
+			So(sets, ShouldHaveLength, 3)
+			So(sets[0].Name, ShouldEqual, "Khans of Tarkir")
+			So(sets[1], ShouldBeNil)
+			So(sets[2].Name, ShouldEqual, "Fate Reforged")
+
+			setErrs, ok := err.(SetErrors)
+			So(ok, ShouldBeTrue)
+			So(setErrs, ShouldHaveLength, 1)
+			So(setErrs["network_issue"], ShouldNotBeNil)
+		})
+
+		Convey("no codes should result in no sets", func() {
+			sets, err := FetchSets()
+			So(err, ShouldBeNil)
+			So(sets, ShouldBeEmpty)
+		})
+	})
+}
+
+func Test_GenerateBoosterContext(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When generating a booster with a canceled context", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK/booster",
+			func(req *http.Request) (*http.Response, error) {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		cards, err := SetCode("KTK").GenerateBoosterContext(ctx)
+		So(cards, ShouldBeNil)
+		So(errors.Is(err, context.Canceled), ShouldBeTrue)
+	})
+}
+
+func Test_SetQueryURL(t *testing.T) {
+	Convey("When building the URL of a set query", t, func() {
+		qry := NewSetQuery().Where(SetBlock, "Khans of Tarkir")
+
+		So(qry.URL(), ShouldEqual, "https://api.magicthegathering.io/v1/sets?block=Khans+of+Tarkir")
+		So(qry.PageURL(1, 500), ShouldEqual, "https://api.magicthegathering.io/v1/sets?block=Khans+of+Tarkir&page=1&pageSize=500")
+	})
+}
+
+func Test_RandomCard(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching a random card of a set", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?pageSize=1&random=true&set=KTK",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Mardu Heart-Piercer","set":"KTK","id":"a"}]}`))
+
+		Convey("the random parameter should be combined with the set filter", func() {
+			card, err := SetCode("KTK").RandomCard()
+			So(err, ShouldBeNil)
+			So(card.Name, ShouldEqual, "Mardu Heart-Piercer")
+			So(card.Set, ShouldEqual, "KTK")
+		})
+
+		Convey("a set without cards should return ErrCardNotFound", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?pageSize=1&random=true&set=XXX",
+				httpmock.NewStringResponder(200, `{"cards":[]}`))
+			card, err := SetCode("XXX").RandomCard()
+			So(card, ShouldBeNil)
+			So(errors.Is(err, ErrCardNotFound), ShouldBeTrue)
+		})
+
+		Convey("errors should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?pageSize=1&random=true&set=KTK",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			_, err := SetCode("KTK").RandomCard()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_Completion(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When computing the completion of a set", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=SOI",
+			httpmock.NewStringResponder(200, `{"cards":[
+				{"name":"Always Watching","number":"1","set":"SOI","id":"a"},
+				{"name":"Archangel Avacyn","number":"5a","set":"SOI","id":"b"},
+				{"name":"Avacyn, the Purifier","number":"5b","set":"SOI","id":"c"},
+				{"name":"Declaration in Stone","number":"12","set":"SOI","id":"d"},
+				{"name":"Promo","set":"SOI","id":"e"}
+			]}`))
+
+		Convey("cards should be matched by id or number", func() {
+			have, total, missing, err := SetCode("SOI").Completion([]string{"a", "12"})
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 4)
+			So(have, ShouldEqual, 2)
+			So(missing, ShouldHaveLength, 2)
+			So(missing[0].Name, ShouldEqual, "Archangel Avacyn")
+			So(missing[1].Name, ShouldEqual, "Promo")
+		})
+
+		Convey("owning one face of a double-faced card should complete it", func() {
+			have, _, _, err := SetCode("SOI").Completion([]string{"c"})
+			So(err, ShouldBeNil)
+			So(have, ShouldEqual, 1)
+
+			have, _, _, err = SetCode("SOI").Completion([]string{"5"})
+			So(err, ShouldBeNil)
+			So(have, ShouldEqual, 1)
+		})
+
+		Convey("an empty collection should miss all cards", func() {
+			have, total, missing, err := SetCode("SOI").Completion(nil)
+			So(err, ShouldBeNil)
+			So(have, ShouldEqual, 0)
+			So(missing, ShouldHaveLength, total)
+		})
+
+		Convey("errors should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=SOI",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			_, _, _, err := SetCode("SOI").Completion(nil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_GenerateBoosters(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When generating several boosters", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK/booster",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Mardu Heart-Piercer","set":"KTK","id":"a"}]}`))
+
+		Convey("all boosters should be generated", func() {
+			boosters, err := SetCode("KTK").GenerateBoosters(context.Background(), BoosterBatch{Count: 24, Concurrency: 4})
+			So(err, ShouldBeNil)
+			So(boosters, ShouldHaveLength, 24)
+			for _, b := range boosters {
+				So(b, ShouldContainCard, "Mardu Heart-Piercer")
+			}
+		})
+
+		Convey("no boosters should be generated for a count of zero", func() {
+			httpmock.ZeroCallCounters()
+			boosters, err := SetCode("KTK").GenerateBoosters(context.Background(), BoosterBatch{})
+			So(err, ShouldBeNil)
+			So(boosters, ShouldBeNil)
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 0)
+		})
+
+		Convey("a negative count should return an error", func() {
+			boosters, err := SetCode("KTK").GenerateBoosters(context.Background(), BoosterBatch{Count: -1})
+			So(err, ShouldNotBeNil)
+			So(boosters, ShouldBeNil)
+		})
+
+		Convey("errors should stop the batch", func() {
+			var calls int32
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK/booster",
+				func(req *http.Request) (*http.Response, error) {
+					if atomic.AddInt32(&calls, 1) == 2 {
+						return nil, errors.New("Network Issue")
+					}
+					return httpmock.NewStringResponse(200, `{"cards":[{"name":"Mardu Heart-Piercer","set":"KTK","id":"a"}]}`), nil
+				})
+			boosters, err := SetCode("KTK").GenerateBoosters(context.Background(), BoosterBatch{Count: 5})
+			So(err, ShouldNotBeNil)
+			So(boosters, ShouldBeNil)
+			So(atomic.LoadInt32(&calls), ShouldEqual, 2)
+		})
+
+		Convey("when the batch is canceled midway", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var calls int32
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK/booster",
+				func(req *http.Request) (*http.Response, error) {
+					if atomic.AddInt32(&calls, 1) == 3 {
+						cancel()
+						<-req.Context().Done()
+						return nil, req.Context().Err()
+					}
+					return httpmock.NewStringResponse(200, `{"cards":[{"name":"Mardu Heart-Piercer","set":"KTK","id":"a"}]}`), nil
+				})
+
+			Convey("no boosters should be returned by default", func() {
+				boosters, err := SetCode("KTK").GenerateBoosters(ctx, BoosterBatch{Count: 5})
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				So(boosters, ShouldBeNil)
+			})
+
+			Convey("the generated boosters should be returned if asked for", func() {
+				boosters, err := SetCode("KTK").GenerateBoosters(ctx, BoosterBatch{Count: 5, Partial: true})
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				So(boosters, ShouldHaveLength, 5)
+				So(boosters[0], ShouldNotBeNil)
+				So(boosters[1], ShouldNotBeNil)
+				So(boosters[2], ShouldBeNil)
+				So(boosters[3], ShouldBeNil)
+				So(boosters[4], ShouldBeNil)
+			})
+		})
+	})
+}
+
+func Test_FetchWithCards(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching a set together with its cards", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK",
+			httpmock.NewStringResponder(200, `{"set":{"code":"KTK","name":"Khans of Tarkir","type":"expansion","releaseDate":"2014-09-26"}}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=KTK",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Mardu Heart-Piercer","set":"KTK","id":"a"},{"name":"Abzan Guide","set":"KTK","id":"b"}]}`))
+
+		Convey("both should be returned", func() {
+			set, cards, err := SetCode("KTK").FetchWithCards()
+			So(err, ShouldBeNil)
+			So(set.Name, ShouldEqual, "Khans of Tarkir")
+			So(cards, ShouldHaveLength, 2)
+		})
+
+		Convey("a failing set request should return an error", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			set, cards, err := SetCode("KTK").FetchWithCards()
+			So(set, ShouldBeNil)
+			So(cards, ShouldBeNil)
+			So(err.Error(), ShouldContainSubstring, "failed to fetch set KTK")
+		})
+
+		Convey("failing requests for both should name both errors", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK",
+				httpmock.NewErrorResponder(errors.New("set issue")))
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=KTK",
+				httpmock.NewErrorResponder(errors.New("cards issue")))
+			_, _, err := SetCode("KTK").FetchWithCards()
+			So(err.Error(), ShouldContainSubstring, "set issue")
+			So(err.Error(), ShouldContainSubstring, "cards issue")
+		})
+	})
+}
+
+func Test_SetExists(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When checking whether a set exists", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/KTK",
+			httpmock.NewStringResponder(200, `{"set":{"code":"KTK","name":"Khans of Tarkir"}}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets/XXX",
+			httpmock.NewStringResponder(404, `{"status":"404","error":"Not Found"}`))
+
+		ok, err := SetCode("KTK").Exists()
+		So(err, ShouldBeNil)
+		So(ok, ShouldBeTrue)
+
+		ok, err = SetCode("XXX").Exists()
+		So(err, ShouldBeNil)
+		So(ok, ShouldBeFalse)
+	})
+}
@@ -0,0 +1,80 @@
+package mtg
+
+import "strings"
+
+// SetType is the type of a set, such as core, expansion or masters. The API can not filter sets
+// by their type, so filter the sets returned by SetQuery.All instead.
+type SetType string
+
+const (
+	// SetTypeCore is the type of core sets like Magic 2010.
+	SetTypeCore = SetType("core")
+	// SetTypeExpansion is the type of regular expansions like Khans of Tarkir.
+	SetTypeExpansion = SetType("expansion")
+	// SetTypeReprint is the type of reprint sets like Chronicles.
+	SetTypeReprint = SetType("reprint")
+	// SetTypeBox is the type of boxed sets like Modern Event Deck.
+	SetTypeBox = SetType("box")
+	// SetTypeUn is the type of the silver-bordered Un-sets.
+	SetTypeUn = SetType("un")
+	// SetTypeFromTheVault is the type of the From the Vault sets.
+	SetTypeFromTheVault = SetType("from the vault")
+	// SetTypePremiumDeck is the type of premium deck series.
+	SetTypePremiumDeck = SetType("premium deck")
+	// SetTypeDuelDeck is the type of duel decks.
+	SetTypeDuelDeck = SetType("duel deck")
+	// SetTypeStarter is the type of starter sets like Portal.
+	SetTypeStarter = SetType("starter")
+	// SetTypeCommander is the type of Commander decks.
+	SetTypeCommander = SetType("commander")
+	// SetTypePlanechase is the type of Planechase sets.
+	SetTypePlanechase = SetType("planechase")
+	// SetTypeArchenemy is the type of Archenemy sets.
+	SetTypeArchenemy = SetType("archenemy")
+	// SetTypePromo is the type of promotional sets.
+	SetTypePromo = SetType("promo")
+	// SetTypeVanguard is the type of Vanguard sets.
+	SetTypeVanguard = SetType("vanguard")
+	// SetTypeMasters is the type of masters sets like Modern Masters.
+	SetTypeMasters = SetType("masters")
+	// SetTypeUnknown is used for all set types which are not known by this package.
+	SetTypeUnknown = SetType("unknown")
+)
+
+var knownSetTypes = map[SetType]bool{
+	SetTypeCore:         true,
+	SetTypeExpansion:    true,
+	SetTypeReprint:      true,
+	SetTypeBox:          true,
+	SetTypeUn:           true,
+	SetTypeFromTheVault: true,
+	SetTypePremiumDeck:  true,
+	SetTypeDuelDeck:     true,
+	SetTypeStarter:      true,
+	SetTypeCommander:    true,
+	SetTypePlanechase:   true,
+	SetTypeArchenemy:    true,
+	SetTypePromo:        true,
+	SetTypeVanguard:     true,
+	SetTypeMasters:      true,
+}
+
+// SetType returns the type of the set, matched case insensitive. Types which are not known are
+// returned as SetTypeUnknown.
+func (s *Set) SetType() SetType {
+	t := s.Type
+	if t == "" {
+		t = s.Expansion
+	}
+	st := SetType(strings.ToLower(strings.TrimSpace(t)))
+	if knownSetTypes[st] {
+		return st
+	}
+	return SetTypeUnknown
+}
+
+// IsExpansion returns true for regular expansions. Together with core sets, which are not
+// expansions, those are the sets of the tournament formats like Standard.
+func (t SetType) IsExpansion() bool {
+	return t == SetTypeExpansion
+}
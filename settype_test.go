@@ -0,0 +1,30 @@
+package mtg
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_SetType(t *testing.T) {
+	Convey("When reading the type of a set", t, func() {
+		Convey("the type of the API should be decoded", func() {
+			var set Set
+			So(json.Unmarshal([]byte(`{"code":"KTK","name":"Khans of Tarkir","type":"expansion"}`), &set), ShouldBeNil)
+			So(set.SetType(), ShouldEqual, SetTypeExpansion)
+			So(set.SetType().IsExpansion(), ShouldBeTrue)
+		})
+		Convey("types should be matched case insensitive", func() {
+			So((&Set{Type: "From The Vault"}).SetType(), ShouldEqual, SetTypeFromTheVault)
+		})
+		Convey("the old expansion field should be used as fallback", func() {
+			So((&Set{Expansion: "core"}).SetType(), ShouldEqual, SetTypeCore)
+			So((&Set{Expansion: "core"}).SetType().IsExpansion(), ShouldBeFalse)
+		})
+		Convey("unknown types should be SetTypeUnknown", func() {
+			So((&Set{Type: "alchemy"}).SetType(), ShouldEqual, SetTypeUnknown)
+			So((&Set{}).SetType(), ShouldEqual, SetTypeUnknown)
+		})
+	})
+}
@@ -0,0 +1,83 @@
+package mtg
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SortCards sorts the cards with the given less function. The sort is stable, so cards which are
+// equal keep their order; sort by a secondary property first to break ties.
+func SortCards(cards []*Card, less func(a, b *Card) bool) {
+	sort.SliceStable(cards, func(i, j int) bool { return less(cards[i], cards[j]) })
+}
+
+// SortCardsByName sorts the cards by name, ignoring the case.
+func SortCardsByName(cards []*Card) {
+	SortCards(cards, func(a, b *Card) bool {
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
+
+// SortCardsByCMC sorts the cards by their converted mana cost.
+func SortCardsByCMC(cards []*Card) {
+	SortCards(cards, func(a, b *Card) bool { return a.CMC < b.CMC })
+}
+
+// SortCardsBySetNumber sorts the cards by set code and by their collector number within a set.
+// Numbers are compared by value and then by their suffix, so "2" comes before "10" and "12a"
+// before "12b". Cards without a number come last.
+func SortCardsBySetNumber(cards []*Card) {
+	SortCards(cards, func(a, b *Card) bool {
+		if a.Set != b.Set {
+			return a.Set < b.Set
+		}
+		return compareNumbers(a.Number, b.Number) < 0
+	})
+}
+
+// SortCardsByPower sorts the cards by power like SortCardsBySetNumber sorts by number: "1+*"
+// comes after "1" and before "2", powers without a value like "*" come after all numeric
+// powers and cards without a power come last.
+func SortCardsByPower(cards []*Card) {
+	SortCards(cards, func(a, b *Card) bool { return compareNumbers(a.Power, b.Power) < 0 })
+}
+
+// compareNumbers compares strings which usually start with a number, like collector numbers or
+// the power of creatures. The leading numbers are compared by value, the rest of the strings
+// lexicographically. Strings without a leading number come after those with a number and empty
+// strings come last.
+func compareNumbers(a, b string) int {
+	if a == "" || b == "" {
+		return strings.Compare(b, a)
+	}
+	na, ra, okA := leadingNumber(a)
+	nb, rb, okB := leadingNumber(b)
+	switch {
+	case okA && !okB:
+		return -1
+	case !okA && okB:
+		return 1
+	case okA && okB && na < nb:
+		return -1
+	case okA && okB && na > nb:
+		return 1
+	}
+	return strings.Compare(ra, rb)
+}
+
+// leadingNumber splits s into the number it starts with and the rest.
+func leadingNumber(s string) (float64, string, bool) {
+	end := 0
+	if strings.HasPrefix(s, "-") {
+		end++
+	}
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.') {
+		end++
+	}
+	n, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0, s, false
+	}
+	return n, s[end:], true
+}
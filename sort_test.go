@@ -0,0 +1,63 @@
+package mtg
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func names(cards []*Card) []string {
+	n := make([]string, len(cards))
+	for i, c := range cards {
+		n[i] = c.Name
+	}
+	return n
+}
+
+func Test_SortCards(t *testing.T) {
+	Convey("When sorting cards", t, func() {
+		Convey("by name the case should be ignored", func() {
+			cards := []*Card{{Name: "shock"}, {Name: "Lightning Bolt"}, {Name: "Ancestral Recall"}}
+			SortCardsByName(cards)
+			So(names(cards), ShouldResemble, []string{"Ancestral Recall", "Lightning Bolt", "shock"})
+		})
+
+		Convey("by cmc equal cards should keep their order", func() {
+			cards := []*Card{{Name: "Craw Wurm", CMC: 6}, {Name: "Shock", CMC: 1}, {Name: "Forest"}, {Name: "Giant Growth", CMC: 1}}
+			SortCardsByCMC(cards)
+			So(names(cards), ShouldResemble, []string{"Forest", "Shock", "Giant Growth", "Craw Wurm"})
+		})
+
+		Convey("by set and number the numbers should be compared by value", func() {
+			cards := []*Card{
+				{Name: "c", Set: "KTK", Number: "100"},
+				{Name: "b", Set: "KTK", Number: "12b"},
+				{Name: "e", Set: "KTK"},
+				{Name: "a", Set: "KTK", Number: "12a"},
+				{Name: "d", Set: "FRF", Number: "200"},
+				{Name: "f", Set: "KTK", Number: "2"},
+			}
+			SortCardsBySetNumber(cards)
+			So(names(cards), ShouldResemble, []string{"d", "f", "a", "b", "c", "e"})
+		})
+
+		Convey("by power variable powers should be sorted after their value", func() {
+			cards := []*Card{
+				{Name: "Tarmogoyf", Power: "*"},
+				{Name: "Lhurgoyf", Power: "1+*"},
+				{Name: "Grizzly Bears", Power: "2"},
+				{Name: "Shock"},
+				{Name: "Spinal Parasite", Power: "-1"},
+				{Name: "Llanowar Elves", Power: "1"},
+			}
+			SortCardsByPower(cards)
+			So(names(cards), ShouldResemble, []string{"Spinal Parasite", "Llanowar Elves", "Lhurgoyf", "Grizzly Bears", "Tarmogoyf", "Shock"})
+		})
+
+		Convey("with a custom function", func() {
+			cards := []*Card{{Name: "a", Artist: "Rush"}, {Name: "b", Artist: "Frazier"}}
+			SortCards(cards, func(a, b *Card) bool { return a.Artist < b.Artist })
+			So(names(cards), ShouldResemble, []string{"b", "a"})
+		})
+	})
+}
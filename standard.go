@@ -0,0 +1,56 @@
+package mtg
+
+import (
+	"sort"
+	"time"
+)
+
+// StandardRule decides whether a set is legal in Standard at the time now.
+type StandardRule func(set *Set, now time.Time) bool
+
+// standardPeriod is how long DefaultStandardRule keeps sets in Standard.
+const standardPeriod = 3 * 365 * 24 * time.Hour
+
+// DefaultStandardRule is the heuristic used by StandardSets. The API knows which formats a card
+// is legal in, but not the rotation of the sets, so the rule guesses it: core sets and
+// expansions which were released in the last three years (and not in the future) are legal.
+// This matches the usual rotation, but is wrong around rotation dates and for special sets
+// which are legal in Standard although they are of another type.
+func DefaultStandardRule(set *Set, now time.Time) bool {
+	switch set.SetType() {
+	case SetTypeCore, SetTypeExpansion:
+	default:
+		return false
+	}
+	released, err := set.Released()
+	if err != nil {
+		return false
+	}
+	return !released.After(now) && now.Sub(released) < standardPeriod
+}
+
+// StandardSets fetches all sets and returns those which are currently legal in Standard
+// according to DefaultStandardRule, sorted by their release date.
+func StandardSets() ([]*Set, error) {
+	return StandardSetsWith(DefaultStandardRule)
+}
+
+// StandardSetsWith works like StandardSets, but decides with rule which sets are legal, for
+// example to apply the actual rotation dates.
+func StandardSetsWith(rule StandardRule) ([]*Set, error) {
+	sets, err := NewSetQuery().All()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var standard []*Set
+	for _, s := range sets {
+		if rule(s, now) {
+			standard = append(standard, s)
+		}
+	}
+	sort.SliceStable(standard, func(i, j int) bool {
+		return standard[i].ReleaseDate < standard[j].ReleaseDate
+	})
+	return standard, nil
+}
@@ -0,0 +1,61 @@
+package mtg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_DefaultStandardRule(t *testing.T) {
+	Convey("When guessing whether a set is legal in Standard", t, func() {
+		now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+		Convey("recent expansions and core sets should be legal", func() {
+			So(DefaultStandardRule(&Set{Type: "expansion", ReleaseDate: "2023-11-17"}, now), ShouldBeTrue)
+			So(DefaultStandardRule(&Set{Type: "core", ReleaseDate: "2021-07-23"}, now), ShouldBeTrue)
+		})
+		Convey("old and unreleased sets should not be legal", func() {
+			So(DefaultStandardRule(&Set{Type: "expansion", ReleaseDate: "2020-09-25"}, now), ShouldBeFalse)
+			So(DefaultStandardRule(&Set{Type: "expansion", ReleaseDate: "2024-08-02"}, now), ShouldBeFalse)
+			So(DefaultStandardRule(&Set{Type: "expansion"}, now), ShouldBeFalse)
+		})
+		Convey("other set types should not be legal", func() {
+			So(DefaultStandardRule(&Set{Type: "masters", ReleaseDate: "2024-01-01"}, now), ShouldBeFalse)
+			So(DefaultStandardRule(&Set{Type: "commander", ReleaseDate: "2024-01-01"}, now), ShouldBeFalse)
+		})
+	})
+}
+
+func Test_StandardSets(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching the Standard sets", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets",
+			httpmock.NewStringResponder(200, `{"sets":[
+				{"code":"BBB","name":"Second","type":"expansion","releaseDate":"2023-02-01"},
+				{"code":"AAA","name":"First","type":"core","releaseDate":"2022-01-01"},
+				{"code":"MMM","name":"Masters","type":"masters","releaseDate":"2023-03-01"}
+			]}`))
+
+		Convey("the rule should decide which sets are returned, sorted by release", func() {
+			sets, err := StandardSetsWith(func(set *Set, _ time.Time) bool {
+				return set.SetType() != SetTypeMasters
+			})
+			So(err, ShouldBeNil)
+			So(sets, ShouldHaveLength, 2)
+			So(sets[0].SetCode, ShouldEqual, "AAA")
+			So(sets[1].SetCode, ShouldEqual, "BBB")
+		})
+
+		Convey("the default rule should be used by StandardSets", func() {
+			sets, err := StandardSets()
+			So(err, ShouldBeNil)
+			for _, s := range sets {
+				So(DefaultStandardRule(s, time.Now()), ShouldBeTrue)
+			}
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package mtg
+
+import "time"
+
+// setCodeBatchSize is the number of set codes SyncSince requests at once. Set codes have at most
+// six characters plus the encoded "|", which keeps the URLs well below the length the API
+// accepts, like multiverseIdBatchSize does for FetchByMultiverseIds.
+const setCodeBatchSize = 40
+
+// SyncSince returns all cards of the sets released on or after since. It is meant to keep a local
+// copy of the cards up to date without downloading all cards again.
+//
+// The API can not tell which cards changed since a given time, so SyncSince uses the release date
+// of the sets instead: it fetches the set list and then the cards of all sets released on or
+// after since. Changes to cards of older sets (like updated oracle texts or rulings) are not found;
+// to catch those a full download is still needed from time to time. Sets without a valid release
+// date may be new as well, so their cards are always included. Many sets are requested in
+// batches one after the other, so the URLs do not get too long for the API.
+func SyncSince(since time.Time) ([]*Card, error) {
+	sets, err := NewSetQuery().All()
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []SetCode
+	for _, s := range sets {
+		if released, err := s.Released(); err == nil && released.Before(since) {
+			continue
+		}
+		codes = append(codes, s.SetCode)
+	}
+
+	var cards []*Card
+	for len(codes) > 0 {
+		n := setCodeBatchSize
+		if n > len(codes) {
+			n = len(codes)
+		}
+		batch, err := NewQuery().WhereSets(codes[:n]...).All()
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, batch...)
+		codes = codes[n:]
+	}
+	return cards, nil
+}
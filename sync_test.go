@@ -0,0 +1,82 @@
+package mtg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_SyncSince(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When syncing the cards released since a date", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets",
+			httpmock.NewStringResponder(200, `{"sets":[
+				{"code":"LEA","name":"Limited Edition Alpha","releaseDate":"1993-08-05"},
+				{"code":"KTK","name":"Khans of Tarkir","releaseDate":"2014-09-26"},
+				{"code":"FRF","name":"Fate Reforged","releaseDate":"2015-01-23"},
+				{"code":"XXX","name":"Unknown"}
+			]}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=KTK%7CFRF%7CXXX",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Mardu Heart-Piercer","set":"KTK","id":"a"},{"name":"Monastery Mentor","set":"FRF","id":"b"}]}`))
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?set=XXX",
+			httpmock.NewStringResponder(200, `{"cards":[{"name":"Mystery Card","set":"XXX","id":"c"}]}`))
+
+		Convey("only the cards of newer sets and sets without release date should be fetched", func() {
+			cards, err := SyncSince(time.Date(2014, 9, 26, 0, 0, 0, 0, time.UTC))
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(cards, ShouldContainCard, "Monastery Mentor")
+		})
+
+		Convey("if no set was released since, only sets without release date should be fetched", func() {
+			httpmock.ZeroCallCounters()
+			cards, err := SyncSince(time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC))
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 1)
+			So(cards, ShouldContainCard, "Mystery Card")
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 2)
+		})
+
+		Convey("many sets should be fetched in batches", func() {
+			sets := `{"sets":[`
+			for i := 0; i < setCodeBatchSize+5; i++ {
+				if i > 0 {
+					sets += ","
+				}
+				sets += fmt.Sprintf(`{"code":"S%02d","releaseDate":"2020-01-01"}`, i)
+			}
+			sets += `]}`
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets",
+				httpmock.NewStringResponder(200, sets))
+			var urls []string
+			httpmock.RegisterRegexpResponder("GET", regexp.MustCompile(`/v1/cards\?set=`),
+				func(req *http.Request) (*http.Response, error) {
+					urls = append(urls, req.URL.Query().Get("set"))
+					return httpmock.NewStringResponse(200, `{"cards":[{"name":"Card","id":"x"}]}`), nil
+				})
+
+			cards, err := SyncSince(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(urls, ShouldHaveLength, 2)
+			So(strings.Count(urls[0], "|"), ShouldEqual, setCodeBatchSize-1)
+			So(urls[1], ShouldEqual, "S40|S41|S42|S43|S44")
+		})
+
+		Convey("errors fetching the sets should be returned", func() {
+			httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/sets",
+				httpmock.NewErrorResponder(errors.New("Network Issue")))
+			_, err := SyncSince(time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
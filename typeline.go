@@ -0,0 +1,165 @@
+package mtg
+
+import "strings"
+
+// typeLineDash separates the types from the subtypes in a type line. It is a long dash as per the
+// Magic rules.
+const typeLineDash = "—"
+
+var supertypes = map[string]bool{
+	"Basic":     true,
+	"Elite":     true,
+	"Host":      true,
+	"Legendary": true,
+	"Ongoing":   true,
+	"Snow":      true,
+	"World":     true,
+}
+
+// TypeLine is the type line of a card split into its parts, like
+// "Legendary Creature — Human Wizard".
+type TypeLine struct {
+	// Supertypes appear to the far left of the type line, like Legendary or Basic.
+	Supertypes []string
+	// Types appear to the left of the dash, like Creature or Instant.
+	Types []string
+	// Subtypes appear to the right of the dash, like Human or Equipment.
+	Subtypes []string
+}
+
+// ParseTypeLine splits a type line into supertypes, types and subtypes. The types are separated
+// from the subtypes by a long dash (—); a hyphen or en dash surrounded by spaces is accepted as
+// well. Every word is treated as a single type.
+func ParseTypeLine(line string) TypeLine {
+	var tl TypeLine
+	left, right := line, ""
+	for _, dash := range []string{typeLineDash, " – ", " - "} {
+		if i := strings.Index(line, dash); i >= 0 {
+			left, right = line[:i], line[i+len(dash):]
+			break
+		}
+	}
+	for _, word := range strings.Fields(left) {
+		if supertypes[word] {
+			tl.Supertypes = append(tl.Supertypes, word)
+		} else {
+			tl.Types = append(tl.Types, word)
+		}
+	}
+	tl.Subtypes = strings.Fields(right)
+	return tl
+}
+
+// String returns the type line as printed on a card, like "Legendary Creature — Human Wizard".
+func (tl TypeLine) String() string {
+	line := strings.Join(append(append([]string{}, tl.Supertypes...), tl.Types...), " ")
+	if len(tl.Subtypes) > 0 {
+		line += " " + typeLineDash + " " + strings.Join(tl.Subtypes, " ")
+	}
+	return line
+}
+
+// TypeLine returns the type line of the card. The supertypes, types and subtypes of the card are
+// used if the card has types, otherwise the Type string is parsed with ParseTypeLine.
+func (c *Card) TypeLine() TypeLine {
+	if len(c.Types) == 0 {
+		return ParseTypeLine(c.Type)
+	}
+	return TypeLine{
+		Supertypes: c.Supertypes,
+		Types:      c.Types,
+		Subtypes:   c.Subtypes,
+	}
+}
+
+// hasType reports whether the type line contains the given type.
+func (tl TypeLine) hasType(t string) bool {
+	for _, typ := range tl.Types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Supertype is a supertype of a card like Legendary, as used by WhereSupertype.
+type Supertype string
+
+const (
+	// SupertypeBasic is the supertype of basic lands.
+	SupertypeBasic = Supertype("Basic")
+	// SupertypeLegendary is the supertype of legendary permanents.
+	SupertypeLegendary = Supertype("Legendary")
+	// SupertypeSnow is the supertype of snow permanents.
+	SupertypeSnow = Supertype("Snow")
+	// SupertypeWorld is the supertype of world enchantments.
+	SupertypeWorld = Supertype("World")
+	// SupertypeOngoing is the supertype of ongoing schemes.
+	SupertypeOngoing = Supertype("Ongoing")
+)
+
+// Type is a card type like Creature, as used by WhereType.
+type Type string
+
+const (
+	// TypeArtifact is the card type of artifacts.
+	TypeArtifact = Type("Artifact")
+	// TypeBattle is the card type of battles.
+	TypeBattle = Type("Battle")
+	// TypeCreature is the card type of creatures.
+	TypeCreature = Type("Creature")
+	// TypeEnchantment is the card type of enchantments.
+	TypeEnchantment = Type("Enchantment")
+	// TypeInstant is the card type of instants.
+	TypeInstant = Type("Instant")
+	// TypeLand is the card type of lands.
+	TypeLand = Type("Land")
+	// TypePlaneswalker is the card type of planeswalkers.
+	TypePlaneswalker = Type("Planeswalker")
+	// TypeSorcery is the card type of sorceries.
+	TypeSorcery = Type("Sorcery")
+	// TypeTribal is the card type of tribal spells like Bitterblossom.
+	TypeTribal = Type("Tribal")
+)
+
+// Subtype is a subtype of a card like Equipment or Goblin, as used by WhereSubtype. There are
+// hundreds of subtypes, so only some common ones have constants; convert other subtypes like
+// Subtype("Homarid") or use Where(CardSubtypes, ...).
+type Subtype string
+
+const (
+	// SubtypeAura is the enchantment subtype of auras.
+	SubtypeAura = Subtype("Aura")
+	// SubtypeEquipment is the artifact subtype of equipment.
+	SubtypeEquipment = Subtype("Equipment")
+	// SubtypeVehicle is the artifact subtype of vehicles.
+	SubtypeVehicle = Subtype("Vehicle")
+	// SubtypeSaga is the enchantment subtype of sagas.
+	SubtypeSaga = Subtype("Saga")
+	// SubtypePlains is the land subtype of Plains.
+	SubtypePlains = Subtype("Plains")
+	// SubtypeIsland is the land subtype of Islands.
+	SubtypeIsland = Subtype("Island")
+	// SubtypeSwamp is the land subtype of Swamps.
+	SubtypeSwamp = Subtype("Swamp")
+	// SubtypeMountain is the land subtype of Mountains.
+	SubtypeMountain = Subtype("Mountain")
+	// SubtypeForest is the land subtype of Forests.
+	SubtypeForest = Subtype("Forest")
+	// SubtypeArcane is the spell subtype of arcane instants and sorceries.
+	SubtypeArcane = Subtype("Arcane")
+	// SubtypeTrap is the instant subtype of traps.
+	SubtypeTrap = Subtype("Trap")
+	// SubtypeHuman is the creature subtype of humans.
+	SubtypeHuman = Subtype("Human")
+	// SubtypeElf is the creature subtype of elves.
+	SubtypeElf = Subtype("Elf")
+	// SubtypeGoblin is the creature subtype of goblins.
+	SubtypeGoblin = Subtype("Goblin")
+	// SubtypeZombie is the creature subtype of zombies.
+	SubtypeZombie = Subtype("Zombie")
+	// SubtypeDragon is the creature subtype of dragons.
+	SubtypeDragon = Subtype("Dragon")
+	// SubtypeWizard is the creature subtype of wizards.
+	SubtypeWizard = Subtype("Wizard")
+)
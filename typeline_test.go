@@ -0,0 +1,49 @@
+package mtg
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_TypeLine(t *testing.T) {
+	Convey("When parsing a type line", t, func() {
+		Convey("supertypes, types and subtypes should be split at the long dash", func() {
+			tl := ParseTypeLine("Legendary Snow Creature — Human Wizard")
+			So(tl.Supertypes, ShouldResemble, []string{"Legendary", "Snow"})
+			So(tl.Types, ShouldResemble, []string{"Creature"})
+			So(tl.Subtypes, ShouldResemble, []string{"Human", "Wizard"})
+		})
+
+		Convey("type lines without subtypes should have none", func() {
+			tl := ParseTypeLine("Artifact Creature")
+			So(tl.Types, ShouldResemble, []string{"Artifact", "Creature"})
+			So(tl.Subtypes, ShouldBeEmpty)
+		})
+
+		Convey("a hyphen should be accepted as dash", func() {
+			tl := ParseTypeLine("Artifact - Equipment")
+			So(tl.Types, ShouldResemble, []string{"Artifact"})
+			So(tl.Subtypes, ShouldResemble, []string{"Equipment"})
+		})
+
+		Convey("the type line should be reconstructed with the long dash", func() {
+			So(ParseTypeLine("Basic Land — Forest").String(), ShouldEqual, "Basic Land — Forest")
+			So(ParseTypeLine("Instant").String(), ShouldEqual, "Instant")
+			So(ParseTypeLine("Tribal Instant - Goblin").String(), ShouldEqual, "Tribal Instant — Goblin")
+		})
+	})
+
+	Convey("When reading the type line of a card", t, func() {
+		Convey("the types of the card should be preferred", func() {
+			card := &Card{Type: "Creature — Yeti", Types: []string{"Creature"}, Subtypes: []string{"Yeti"}}
+			So(card.TypeLine().String(), ShouldEqual, "Creature — Yeti")
+		})
+
+		Convey("the type should be parsed if the types are missing", func() {
+			card := &Card{Type: "Legendary Creature — Elf Warrior"}
+			So(card.TypeLine().Supertypes, ShouldResemble, []string{"Legendary"})
+			So(card.TypeLine().Subtypes, ShouldResemble, []string{"Elf", "Warrior"})
+		})
+	})
+}
@@ -1,73 +1,54 @@
 package mtg
 
 import (
+	"context"
 	"encoding/json"
-	"net/http"
 )
 
 // GetTypes fetches a list of all card types
 func GetTypes() ([]string, error) {
-	resp, err := http.Get(queryUrl + "types")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if err := checkError(resp); err != nil {
-		return nil, err
-	}
-	res := new(struct {
-		Types []string `json:"types"`
-	})
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&res); err != nil {
-		return nil, err
-	}
-	return res.Types, nil
+	return GetTypesContext(context.Background())
+}
+
+// GetTypesContext works like GetTypes. The request is aborted when ctx is done.
+func GetTypesContext(ctx context.Context) ([]string, error) {
+	return fetchList(ctx, "types")
 }
 
 // GetSuperTypes fetches a list of all card supertypes
 func GetSuperTypes() ([]string, error) {
-	resp, err := http.Get(queryUrl + "supertypes")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if err := checkError(resp); err != nil {
-		return nil, err
-	}
-	res := new(struct {
-		Types []string `json:"supertypes"`
-	})
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&res); err != nil {
-		return nil, err
-	}
-	return res.Types, nil
+	return GetSuperTypesContext(context.Background())
+}
+
+// GetSuperTypesContext works like GetSuperTypes. The request is aborted when ctx is done.
+func GetSuperTypesContext(ctx context.Context) ([]string, error) {
+	return fetchList(ctx, "supertypes")
 }
 
 // GetSubTypes fetches a list of all card subtypes
 func GetSubTypes() ([]string, error) {
-	resp, err := http.Get(queryUrl + "subtypes")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if err := checkError(resp); err != nil {
-		return nil, err
-	}
-	res := new(struct {
-		Types []string `json:"subtypes"`
-	})
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&res); err != nil {
-		return nil, err
-	}
-	return res.Types, nil
+	return GetSubTypesContext(context.Background())
+}
+
+// GetSubTypesContext works like GetSubTypes. The request is aborted when ctx is done.
+func GetSubTypesContext(ctx context.Context) ([]string, error) {
+	return fetchList(ctx, "subtypes")
 }
 
 // GetFormats fetches a list of all known game formats
 func GetFormats() ([]string, error) {
-	resp, err := http.Get(queryUrl + "formats")
+	return GetFormatsContext(context.Background())
+}
+
+// GetFormatsContext works like GetFormats. The request is aborted when ctx is done.
+func GetFormatsContext(ctx context.Context) ([]string, error) {
+	return fetchList(ctx, "formats")
+}
+
+// fetchList fetches one of the list endpoints of the API, whose responses contain the list under
+// the name of the endpoint, like {"types":[...]}.
+func fetchList(ctx context.Context, endpoint string) ([]string, error) {
+	resp, err := httpGet(ctx, baseUrl()+endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -75,12 +56,16 @@ func GetFormats() ([]string, error) {
 	if err := checkError(resp); err != nil {
 		return nil, err
 	}
-	res := new(struct {
-		Formats []string `json:"formats"`
-	})
+	var res map[string]json.RawMessage
 	decoder := json.NewDecoder(resp.Body)
 	if err := decoder.Decode(&res); err != nil {
 		return nil, err
 	}
-	return res.Formats, nil
+	var list []string
+	if raw, ok := res[endpoint]; ok {
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
 }
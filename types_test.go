@@ -1,7 +1,9 @@
 package mtg
 
 import (
+	"context"
 	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -191,3 +193,39 @@ func Test_GetFormats(t *testing.T) {
 		})
 	})
 }
+
+func Test_GetTypesContext(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When fetching the type lists with a context", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/formats",
+			httpmock.NewStringResponder(200, `{"formats":["Commander","Legacy","Modern"]}`))
+
+		Convey("the list should be returned", func() {
+			formats, err := GetFormatsContext(context.Background())
+			So(err, ShouldBeNil)
+			So(formats, ShouldResemble, []string{"Commander", "Legacy", "Modern"})
+		})
+
+		Convey("a canceled context should abort the request", func() {
+			for _, endpoint := range []string{"types", "supertypes", "subtypes", "formats"} {
+				httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/"+endpoint,
+					func(req *http.Request) (*http.Response, error) {
+						<-req.Context().Done()
+						return nil, req.Context().Err()
+					})
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			for _, fetch := range []func(context.Context) ([]string, error){
+				GetTypesContext, GetSuperTypesContext, GetSubTypesContext, GetFormatsContext,
+			} {
+				list, err := fetch(ctx)
+				So(list, ShouldBeNil)
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			}
+		})
+	})
+}
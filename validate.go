@@ -0,0 +1,74 @@
+package mtg
+
+import (
+	"fmt"
+	"log"
+)
+
+// WithPageValidation enables sanity checks of the pages fetched with Query.Page, Query.PageS and
+// Query.PageResult. The API occasionally sends a Total-Count header which contradicts the cards
+// of a page or its next link; such contradictions are logged as warnings to logger, while the
+// page is still returned unchanged. A nil logger disables the checks, which is the default.
+func WithPageValidation(logger *log.Logger) Option {
+	return func(c *config) {
+		c.pageLogger = logger
+	}
+}
+
+// pageInfo is what the API declared about a fetched page, before client side filters are
+// applied.
+type pageInfo struct {
+	page     int
+	pageSize int
+	count    int
+	total    int
+	hasTotal bool
+	hasLink  bool
+	hasNext  bool
+}
+
+// warnings returns a description of every contradiction between the cards of the page and the
+// headers of the response.
+func (p pageInfo) warnings() []string {
+	var warnings []string
+	if p.count > p.pageSize {
+		warnings = append(warnings, fmt.Sprintf("page %d has %d cards, more than the page size %d", p.page, p.count, p.pageSize))
+	}
+	if !p.hasTotal {
+		return warnings
+	}
+	before := (p.page - 1) * p.pageSize
+	if before+p.count > p.total {
+		warnings = append(warnings, fmt.Sprintf("page %d has %d cards, but Total-Count %d leaves room for %d", p.page, p.count, p.total, max0(p.total-before)))
+	}
+	more := p.page*p.pageSize < p.total
+	if more && p.count < p.pageSize {
+		warnings = append(warnings, fmt.Sprintf("page %d has only %d cards, but Total-Count %d declares more pages", p.page, p.count, p.total))
+	}
+	if p.hasLink && p.hasNext != more {
+		if p.hasNext {
+			warnings = append(warnings, fmt.Sprintf("page %d has a next link, but Total-Count %d declares it the last page", p.page, p.total))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("page %d has no next link, but Total-Count %d declares more pages", p.page, p.total))
+		}
+	}
+	return warnings
+}
+
+func max0(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// validatePage logs the warnings of the page if page validation is enabled.
+func validatePage(p pageInfo) {
+	logger := currentConfig().pageLogger
+	if logger == nil {
+		return
+	}
+	for _, w := range p.warnings() {
+		logger.Printf("mtg: %s", w)
+	}
+}
@@ -0,0 +1,69 @@
+package mtg
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_PageInfoWarnings(t *testing.T) {
+	Convey("When validating a page", t, func() {
+		Convey("a consistent page should have no warnings", func() {
+			p := pageInfo{page: 2, pageSize: 10, count: 10, total: 25, hasTotal: true, hasLink: true, hasNext: true}
+			So(p.warnings(), ShouldBeEmpty)
+			p = pageInfo{page: 3, pageSize: 10, count: 5, total: 25, hasTotal: true, hasLink: true}
+			So(p.warnings(), ShouldBeEmpty)
+		})
+		Convey("too many cards should be reported", func() {
+			p := pageInfo{page: 1, pageSize: 10, count: 12}
+			So(p.warnings(), ShouldResemble, []string{"page 1 has 12 cards, more than the page size 10"})
+		})
+		Convey("cards beyond the total should be reported", func() {
+			p := pageInfo{page: 3, pageSize: 10, count: 10, total: 25, hasTotal: true}
+			So(p.warnings(), ShouldResemble, []string{"page 3 has 10 cards, but Total-Count 25 leaves room for 5"})
+		})
+		Convey("a short page before the last should be reported", func() {
+			p := pageInfo{page: 1, pageSize: 10, count: 4, total: 25, hasTotal: true}
+			So(p.warnings(), ShouldResemble, []string{"page 1 has only 4 cards, but Total-Count 25 declares more pages"})
+		})
+		Convey("next links contradicting the total should be reported", func() {
+			p := pageInfo{page: 3, pageSize: 10, count: 5, total: 25, hasTotal: true, hasLink: true, hasNext: true}
+			So(p.warnings(), ShouldResemble, []string{"page 3 has a next link, but Total-Count 25 declares it the last page"})
+			p = pageInfo{page: 1, pageSize: 10, count: 10, total: 25, hasTotal: true, hasLink: true}
+			So(p.warnings(), ShouldResemble, []string{"page 1 has no next link, but Total-Count 25 declares more pages"})
+		})
+	})
+}
+
+func Test_PageValidation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("When the API sends a contradicting Total-Count", t, func() {
+		httpmock.RegisterResponder("GET", "https://api.magicthegathering.io/v1/cards?name=Bolt&page=1&pageSize=1",
+			NewStringResponderWithHeader(200, `{"cards":[{"name":"Lightning Bolt","set":"LEA","id":"a"},{"name":"Chain Lightning","set":"LEG","id":"b"}]}`,
+				map[string]string{"Total-Count": "1"}))
+
+		Convey("without validation nothing should be logged", func() {
+			cards, total, err := NewQuery().Where(CardName, "Bolt").PageS(1, 1)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(total, ShouldEqual, 1)
+		})
+
+		Convey("with validation the contradictions should be logged", func() {
+			var buf bytes.Buffer
+			Configure(WithPageValidation(log.New(&buf, "", 0)))
+			defer Configure(WithPageValidation(nil))
+
+			cards, _, err := NewQuery().Where(CardName, "Bolt").PageS(1, 1)
+			So(err, ShouldBeNil)
+			So(cards, ShouldHaveLength, 2)
+			So(buf.String(), ShouldEqual, "mtg: page 1 has 2 cards, more than the page size 1\n"+
+				"mtg: page 1 has 2 cards, but Total-Count 1 leaves room for 1\n")
+		})
+	})
+}